@@ -0,0 +1,86 @@
+package rich
+
+import (
+	"strings"
+	"unicode"
+)
+
+// bannerFill is what the letters of a Banner are drawn with.
+const bannerFill = "█"
+
+// bannerFont is a 5-row block font; # marks a filled cell.
+var bannerFont = map[rune][5]string{
+	'A': {" ### ", "#   #", "#####", "#   #", "#   #"},
+	'B': {"#### ", "#   #", "#### ", "#   #", "#### "},
+	'C': {" ####", "#    ", "#    ", "#    ", " ####"},
+	'D': {"#### ", "#   #", "#   #", "#   #", "#### "},
+	'E': {"#####", "#    ", "#### ", "#    ", "#####"},
+	'F': {"#####", "#    ", "#### ", "#    ", "#    "},
+	'G': {" ####", "#    ", "#  ##", "#   #", " ####"},
+	'H': {"#   #", "#   #", "#####", "#   #", "#   #"},
+	'I': {"###", " # ", " # ", " # ", "###"},
+	'J': {"  ###", "   # ", "   # ", "#  # ", " ##  "},
+	'K': {"#   #", "#  # ", "###  ", "#  # ", "#   #"},
+	'L': {"#    ", "#    ", "#    ", "#    ", "#####"},
+	'M': {"#   #", "## ##", "# # #", "#   #", "#   #"},
+	'N': {"#   #", "##  #", "# # #", "#  ##", "#   #"},
+	'O': {" ### ", "#   #", "#   #", "#   #", " ### "},
+	'P': {"#### ", "#   #", "#### ", "#    ", "#    "},
+	'Q': {" ### ", "#   #", "# # #", "#  # ", " ## #"},
+	'R': {"#### ", "#   #", "#### ", "#  # ", "#   #"},
+	'S': {" ####", "#    ", " ### ", "    #", "#### "},
+	'T': {"#####", "  #  ", "  #  ", "  #  ", "  #  "},
+	'U': {"#   #", "#   #", "#   #", "#   #", " ### "},
+	'V': {"#   #", "#   #", "#   #", " # # ", "  #  "},
+	'W': {"#   #", "#   #", "# # #", "## ##", "#   #"},
+	'X': {"#   #", " # # ", "  #  ", " # # ", "#   #"},
+	'Y': {"#   #", " # # ", "  #  ", "  #  ", "  #  "},
+	'Z': {"#####", "   # ", "  #  ", " #   ", "#####"},
+	'0': {" ### ", "#  ##", "# # #", "##  #", " ### "},
+	'1': {" # ", "## ", " # ", " # ", "###"},
+	'2': {" ### ", "#   #", "  ## ", " #   ", "#####"},
+	'3': {"#### ", "    #", " ### ", "    #", "#### "},
+	'4': {"#   #", "#   #", "#####", "    #", "    #"},
+	'5': {"#####", "#    ", "#### ", "    #", "#### "},
+	'6': {" ### ", "#    ", "#### ", "#   #", " ### "},
+	'7': {"#####", "    #", "   # ", "  #  ", "  #  "},
+	'8': {" ### ", "#   #", " ### ", "#   #", " ### "},
+	'9': {" ### ", "#   #", " ####", "    #", " ### "},
+}
+
+// Banner renders text in large block letters, five lines per line of
+// text. Letters are case-insensitive; anything outside A-Z and 0-9 becomes
+// a blank block. The result is plain text, so it can be colored with
+// Gradient, Rainbow or markup.
+func Banner(text string) string {
+	if text == "" {
+		return ""
+	}
+
+	var banners []string
+	for _, line := range strings.Split(text, "\n") {
+		var rows [5]strings.Builder
+		for index, char := range line {
+			glyph, ok := bannerFont[unicode.ToUpper(char)]
+			if !ok {
+				glyph = [5]string{"     ", "     ", "     ", "     ", "     "}
+			}
+			for row := range rows {
+				if index > 0 {
+					rows[row].WriteByte(' ')
+				}
+				rows[row].WriteString(strings.ReplaceAll(glyph[row], "#", bannerFill))
+			}
+		}
+
+		for _, row := range rows {
+			banners = append(banners, strings.TrimRight(row.String(), " "))
+		}
+	}
+
+	return strings.Join(banners, "\n")
+}
+
+func PrintBanner(text string) {
+	writeOutput(config().output, Banner(text)+"\n")
+}
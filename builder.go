@@ -0,0 +1,90 @@
+package rich
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Text is styled text built by chaining, e.g. New("hi").Red().Bold().Print().
+// Each method returns a new Text, so a partly styled Text can be reused.
+type Text struct {
+	text   string
+	styles []string
+}
+
+// New starts a Text. Markup in text is still interpreted, with the chained
+// styles applied around it.
+func New(text string) Text {
+	return Text{text: text}
+}
+
+// Style adds any tag markup, such as "#ff8800" or "bg:blue"; invalid markup
+// is ignored.
+func (t Text) Style(markup string) Text {
+	if !validTags(markup) {
+		return t
+	}
+
+	return Text{text: t.text, styles: append(slices.Clip(t.styles), markup)}
+}
+
+func (t Text) Background(color string) Text { return t.Style("bg:" + color) }
+
+func (t Text) Bold() Text          { return t.Style("b") }
+func (t Text) Dim() Text           { return t.Style("dim") }
+func (t Text) Italic() Text        { return t.Style("i") }
+func (t Text) Underline() Text     { return t.Style("u") }
+func (t Text) Strikethrough() Text { return t.Style("s") }
+func (t Text) Blink() Text         { return t.Style("blink") }
+func (t Text) Inverse() Text       { return t.Style("x") }
+func (t Text) Hidden() Text        { return t.Style("hidden") }
+func (t Text) Overline() Text      { return t.Style("overline") }
+
+func (t Text) Black() Text   { return t.Style("black") }
+func (t Text) Red() Text     { return t.Style("red") }
+func (t Text) Green() Text   { return t.Style("green") }
+func (t Text) Yellow() Text  { return t.Style("yellow") }
+func (t Text) Blue() Text    { return t.Style("blue") }
+func (t Text) Magenta() Text { return t.Style("magenta") }
+func (t Text) Cyan() Text    { return t.Style("cyan") }
+func (t Text) White() Text   { return t.Style("white") }
+func (t Text) Gray() Text    { return t.Style("gray") }
+
+// String renders the text with its styles applied.
+func (t Text) String() string {
+	if len(t.styles) == 0 {
+		return parseTags(t.text)
+	}
+
+	return parseTags("[" + strings.Join(t.styles, " ") + "]" + t.text + "[/]")
+}
+
+func (t Text) Print() {
+	writeOutput(config().output, t.String()+"\n")
+}
+
+// StyleIf renders text, which may contain markup, in trueStyle when cond
+// holds and in falseStyle otherwise. An empty or invalid style leaves the
+// text unstyled.
+func StyleIf(text string, cond bool, trueStyle, falseStyle string) string {
+	if cond {
+		return New(text).Style(trueStyle).String()
+	}
+
+	return New(text).Style(falseStyle).String()
+}
+
+// StyleSigned renders n as Print formats numbers, green when it is above
+// zero and red when below. Zero and NaN are unstyled.
+func StyleSigned(n float64) string {
+	text := numberText(reflect.ValueOf(n))
+	switch {
+	case n > 0:
+		return New(text).Green().String()
+	case n < 0:
+		return New(text).Red().String()
+	}
+
+	return text
+}
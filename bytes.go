@@ -0,0 +1,136 @@
+package rich
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// HexDump styles for the offset column and the ASCII gutter.
+const (
+	hexDumpOffsetStyle = "blue"
+	hexDumpASCIIStyle  = "dim"
+)
+
+// BytesFormat selects how byte slices are printed.
+type BytesFormat int
+
+const (
+	// BytesAuto prints readable text as a string and anything else as hex.
+	BytesAuto BytesFormat = iota
+	BytesString
+	BytesHex
+	// BytesNumbers prints bytes like any other slice of numbers.
+	BytesNumbers
+)
+
+// SetBytesFormat sets how []byte values are printed (BytesAuto by default).
+func SetBytesFormat(format BytesFormat) {
+	configure(func(s *settings) { s.bytesFormat = format })
+}
+
+// formatBytes renders a []byte or byte array as a quoted string or as
+// 0x-prefixed hex, reporting false when it should be printed as a plain
+// slice.
+func (s *settings) formatBytes(value reflect.Value) (string, bool) {
+	format := s.bytesFormat
+	if format == BytesNumbers {
+		return "", false
+	}
+
+	// Named byte types, arrays and unaddressable values cannot be read with
+	// Bytes or Copy into a []byte, so the elements are read one by one.
+	data := make([]byte, value.Len())
+	for index := range data {
+		data[index] = byte(value.Index(index).Uint())
+	}
+
+	if format == BytesAuto {
+		format = BytesHex
+		if isText(data) {
+			format = BytesString
+		}
+	}
+
+	switch format {
+	case BytesString:
+		return s.styleEscape("green") + strconv.Quote(string(data)) + s.resetEscape(), true
+	case BytesHex:
+		return s.styleEscape("cyan") + "0x" + hex.EncodeToString(data) + s.resetEscape(), true
+	}
+
+	return "", false
+}
+
+func isText(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+
+	for _, char := range string(data) {
+		if !unicode.IsPrint(char) && !unicode.IsSpace(char) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// HexDump renders data like hexdump -C: an offset column, sixteen bytes per
+// line in two groups of eight, and an ASCII gutter that shows non-printable
+// bytes as ".".
+func HexDump(data []byte) string {
+	const digits = "0123456789abcdef"
+
+	s := config()
+	offsetStyle, asciiStyle, reset := s.styleEscape(hexDumpOffsetStyle), s.styleEscape(hexDumpASCIIStyle), s.resetEscape()
+	lines := (len(data) + 15) / 16
+	var result strings.Builder
+	result.Grow(lines * (78 + len(offsetStyle) + len(asciiStyle) + 2*len(reset)))
+
+	for offset := 0; offset < len(data); offset += 16 {
+		line := data[offset:min(offset+16, len(data))]
+
+		result.WriteString(offsetStyle)
+		fmt.Fprintf(&result, "%08x", offset)
+		result.WriteString(reset)
+		result.WriteString("  ")
+		for index := range 16 {
+			if index == 8 {
+				result.WriteByte(' ')
+			}
+			if index < len(line) {
+				result.WriteByte(digits[line[index]>>4])
+				result.WriteByte(digits[line[index]&0x0f])
+				result.WriteByte(' ')
+			} else {
+				result.WriteString("   ")
+			}
+		}
+
+		result.WriteByte(' ')
+		result.WriteString(asciiStyle)
+		result.WriteByte('|')
+		for _, char := range line {
+			if char < 0x20 || char > 0x7e {
+				char = '.'
+			}
+			result.WriteByte(char)
+		}
+		result.WriteByte('|')
+		result.WriteString(reset)
+		if offset+16 < len(data) {
+			result.WriteByte('\n')
+		}
+	}
+
+	return result.String()
+}
+
+func PrintHexDump(data []byte) {
+	writeOutput(config().output, HexDump(data)+"\n")
+}
@@ -0,0 +1,147 @@
+package rich
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// Gradient colors each visible rune of text with a foreground
+// interpolated from startHex to endHex, followed by a reset. ANSI sequences
+// already in text are kept and not counted. Text is returned unchanged when
+// colors are disabled or either color is not valid hex.
+func Gradient(text, startHex, endHex string) string {
+	startR, startG, startB, ok := hexToRGB(startHex)
+	if !ok {
+		return text
+	}
+	endR, endG, endB, ok := hexToRGB(endHex)
+	if !ok {
+		return text
+	}
+
+	steps := max(utf8.RuneCountInString(stripANSI(text))-1, 1)
+
+	return colorRunes(text, func(index int) (uint8, uint8, uint8) {
+		t := float64(index) / float64(steps)
+		return lerp(startR, endR, t), lerp(startG, endG, t), lerp(startB, endB, t)
+	})
+}
+
+// Blend mixes two #rgb or #rrggbb colors, returning #rrggbb: t is the share
+// of b, clamped to [0, 1], so 0 gives a and 1 gives b. a is returned
+// unchanged when either color is not valid hex with its leading #.
+func Blend(a, b string, t float64) string {
+	fromR, fromG, fromB, ok := hashHexToRGB(a)
+	if !ok {
+		return a
+	}
+	toR, toG, toB, ok := hashHexToRGB(b)
+	if !ok {
+		return a
+	}
+
+	t = math.Min(math.Max(t, 0), 1)
+
+	return fmt.Sprintf("#%02x%02x%02x", lerp(fromR, toR, t), lerp(fromG, toG, t), lerp(fromB, toB, t))
+}
+
+// hashHexToRGB is hexToRGB for colors that must start with #, so a word such
+// as "bad" is not read as the short form of #bbaadd.
+func hashHexToRGB(color string) (uint8, uint8, uint8, bool) {
+	if !strings.HasPrefix(color, "#") {
+		return 0, 0, 0, false
+	}
+
+	return hexToRGB(color)
+}
+
+// Lighten moves hex toward white by amount, a fraction from 0 to 1; 0.2
+// lightens it by 20%.
+func Lighten(hex string, amount float64) string {
+	return Blend(hex, "#ffffff", amount)
+}
+
+// Darken moves hex toward black by amount, like Lighten.
+func Darken(hex string, amount float64) string {
+	return Blend(hex, "#000000", amount)
+}
+
+// Rainbow colors each visible rune of text with the next hue around the HSV
+// wheel, spreading one full turn across the text, followed by a reset.
+func Rainbow(text string) string {
+	count := max(utf8.RuneCountInString(stripANSI(text)), 1)
+
+	return colorRunes(text, func(index int) (uint8, uint8, uint8) {
+		return hsvToRGB(360*float64(index)/float64(count), RainbowSaturation, RainbowValue)
+	})
+}
+
+// RainbowSaturation and RainbowValue are the HSV saturation and value
+// Rainbow colors with. Values outside [0, 1] are clamped.
+var (
+	RainbowSaturation = 0.8
+	RainbowValue      = 1.0
+)
+
+// colorRunes wraps every visible rune of text in the escape for the color
+// picked by color, downgraded to the color profile, copying ANSI sequences through untouched.
+func colorRunes(text string, color func(index int) (uint8, uint8, uint8)) string {
+	if !config().color || text == "" {
+		return text
+	}
+
+	var result strings.Builder
+	index := 0
+	writeRunes := func(plain string) {
+		for _, r := range plain {
+			red, green, blue := color(index)
+			result.WriteString("\033[" + rgbCode(red, green, blue, false) + "m")
+			result.WriteRune(r)
+			index++
+		}
+	}
+
+	position := 0
+	for _, loc := range ansiRe.FindAllStringIndex(text, -1) {
+		writeRunes(text[position:loc[0]])
+		result.WriteString(text[loc[0]:loc[1]])
+		position = loc[1]
+	}
+	writeRunes(text[position:])
+	result.WriteString("\033[0m")
+
+	return result.String()
+}
+
+func lerp(from, to uint8, t float64) uint8 {
+	return uint8(float64(from) + (float64(to)-float64(from))*t + 0.5)
+}
+
+func hsvToRGB(hue, saturation, value float64) (uint8, uint8, uint8) {
+	saturation = math.Min(math.Max(saturation, 0), 1)
+	value = math.Min(math.Max(value, 0), 1)
+
+	chroma := value * saturation
+	x := chroma * (1 - math.Abs(math.Mod(hue/60, 2)-1))
+	m := value - chroma
+
+	var r, g, b float64
+	switch {
+	case hue < 60:
+		r, g, b = chroma, x, 0
+	case hue < 120:
+		r, g, b = x, chroma, 0
+	case hue < 180:
+		r, g, b = 0, chroma, x
+	case hue < 240:
+		r, g, b = 0, x, chroma
+	case hue < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+
+	return uint8((r+m)*255 + 0.5), uint8((g+m)*255 + 0.5), uint8((b+m)*255 + 0.5)
+}
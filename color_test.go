@@ -0,0 +1,26 @@
+package rich
+
+import "testing"
+
+func TestBlend(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"halfway", Blend("#000000", "#ffffff", 0.5), "#808080"},
+		{"short form", Blend("#f00", "#00f", 0), "#ff0000"},
+		{"clamped", Blend("#000", "#fff", 2), "#ffffff"},
+		{"lighten", Lighten("#000000", 0.2), "#333333"},
+		{"darken", Darken("#ffffff", 0.2), "#cccccc"},
+		{"missing #", Darken("bad", 0.1), "bad"},
+		{"missing # on b", Blend("#000000", "fff", 0.5), "#000000"},
+		{"invalid", Lighten("#zzzzzz", 0.5), "#zzzzzz"},
+	}
+
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, test.got, test.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package rich
+
+import "strings"
+
+// ColumnOrder selects how Columns fills its grid.
+type ColumnOrder int
+
+const (
+	// ColumnMajor fills each column top to bottom, like ls.
+	ColumnMajor ColumnOrder = iota
+	// RowMajor fills each row left to right.
+	RowMajor
+)
+
+// SetColumnOrder sets how Columns fills its grid (ColumnMajor by default).
+func SetColumnOrder(order ColumnOrder) {
+	configure(func(s *settings) { s.columnOrder = order })
+}
+
+// Columns arranges items, which may contain markup, into as many columns as
+// fit in width visible columns, or in TerminalWidth when width is zero or
+// less. Every cell is padded to the widest item.
+func Columns(items []string, width int) string {
+	if len(items) == 0 {
+		return ""
+	}
+	if width <= 0 {
+		width = TerminalWidth()
+	}
+	s := config()
+	const gap = 2
+
+	rendered := make([]string, len(items))
+	cell := 0
+	for index, item := range items {
+		rendered[index] = s.parseTags(item)
+		cell = max(cell, VisibleWidth(rendered[index]))
+	}
+
+	columns := max((width+gap)/(cell+gap), 1)
+	rows := (len(items) + columns - 1) / columns
+	columns = (len(items) + rows - 1) / rows
+
+	lines := make([]string, rows)
+	for row := range rows {
+		var line strings.Builder
+		cellIndex := func(column int) int {
+			if s.columnOrder == ColumnMajor {
+				return column*rows + row
+			}
+			return row*columns + column
+		}
+
+		for column := 0; column < columns && cellIndex(column) < len(items); column++ {
+			if column > 0 {
+				line.WriteString(strings.Repeat(" ", gap))
+			}
+			// The last cell of a line is not padded, so lines carry no
+			// trailing spaces.
+			if column+1 < columns && cellIndex(column+1) < len(items) {
+				line.WriteString(Pad(rendered[cellIndex(column)], cell, AlignLeft, ' '))
+			} else {
+				line.WriteString(rendered[cellIndex(column)])
+			}
+		}
+		lines[row] = line.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func PrintColumns(items []string, width int) {
+	writeOutput(config().output, Columns(items, width)+"\n")
+}
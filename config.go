@@ -0,0 +1,134 @@
+package rich
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// settings is the package-wide configuration. A published settings value is
+// never modified: setters copy it, change the copy and publish the result,
+// so formatting can read it from any goroutine without locking.
+type settings struct {
+	color            bool
+	maxDepth         int
+	indentWidth      int
+	timeLayout       string
+	keywordHighlight bool
+	keywords         map[string]compiledKeyword
+	theme            Theme
+	styles           map[string]Style
+	disabled         map[string]bool
+	bytesFormat      BytesFormat
+	numberFormat     NumberFormat
+	floatPrecision   int
+	quoteStyle       QuoteStyle
+	jsonAuto         bool
+	maxFields        int
+	truncateFields   bool
+	sortMapKeys      bool
+	runeChars        bool
+	colorProfile     ColorProfile
+	output           io.Writer
+	colorSet         bool
+	terminalWidth    int
+
+	// Styles and layout of the components built on Print, each changed
+	// through its own setter.
+	panel       PanelStyle
+	columnOrder ColumnOrder
+	rule        RuleStyle
+	tree        TreeStyle
+	progress    ProgressStyle
+}
+
+var (
+	configMu sync.Mutex
+	current  = newConfig(settings{
+		// Per no-color.org, any non-empty NO_COLOR disables ANSI output while
+		// tags are still parsed and stripped. Otherwise colors are only emitted
+		// when stdout is a terminal, unless FORCE_COLOR is set.
+		color:        detectColor(os.Stdout),
+		colorProfile: detectColorProfile(),
+		// Bounding the depth also keeps cyclic data from recursing forever.
+		maxDepth:         10,
+		indentWidth:      2,
+		timeLayout:       time.RFC3339,
+		keywordHighlight: true,
+		keywords:         map[string]compiledKeyword{},
+		theme:            defaultTheme(),
+		output:           os.Stdout,
+		floatPrecision:   -1,
+		sortMapKeys:      true,
+
+		panel:    PanelStyle{Box: RoundedBox},
+		rule:     RuleStyle{Rune: '─', Line: "dim", Title: "b"},
+		tree:     TreeStyle{Guide: "dim", Key: "key"},
+		progress: ProgressStyle{Filled: "green", Empty: "dim"},
+	})
+)
+
+// settingsFor returns the settings to render output for w with. Unless
+// SetColorEnabled decided otherwise, colors are detected for w itself, so a
+// log file or buffer gets plain text even when stdout is a terminal.
+func settingsFor(w io.Writer) *settings {
+	s := config()
+	if s.colorSet || sameWriter(w, s.output) {
+		return s
+	}
+
+	next := *s
+	next.color = detectColor(w)
+
+	return &next
+}
+
+// sameWriter reports whether a and b are the same writer. Comparing
+// interfaces panics when both hold the same uncomparable type, such as a
+// struct with a slice field, so those are never treated as equal.
+func sameWriter(a, b io.Writer) bool {
+	value := reflect.ValueOf(a)
+	if !value.IsValid() || !value.Comparable() {
+		return false
+	}
+
+	return a == b
+}
+
+// outputMu makes every write from this package a single atomic call.
+var outputMu sync.Mutex
+
+func newConfig(initial settings) *atomic.Pointer[settings] {
+	var pointer atomic.Pointer[settings]
+	pointer.Store(&initial)
+
+	return &pointer
+}
+
+func config() *settings {
+	return current.Load()
+}
+
+// configure applies change to a copy of the current settings and publishes
+// it. Maps must be cloned before they are modified.
+func configure(change func(*settings)) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	next := *current.Load()
+	change(&next)
+	current.Store(&next)
+}
+
+// writeOutput writes s to w in one call under the output lock, so lines
+// printed from different goroutines never interleave. The order of lines
+// across goroutines is not guaranteed.
+func writeOutput(w io.Writer, s string) (int, error) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+
+	return io.WriteString(w, s)
+}
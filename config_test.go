@@ -0,0 +1,21 @@
+package rich
+
+import "testing"
+
+// sliceWriter is a value writer that cannot be compared with ==.
+type sliceWriter struct{ lines []string }
+
+func (w sliceWriter) Write(p []byte) (int, error) { return len(p), nil }
+
+func TestUncomparableOutput(t *testing.T) {
+	previous := config()
+	t.Cleanup(func() { current.Store(previous) })
+
+	SetOutput(sliceWriter{})
+	Print("[red]x")
+	Info("message")
+	if _, err := Fprint(sliceWriter{}, "y"); err != nil {
+		t.Fatal(err)
+	}
+	PrintWith(Options{Output: sliceWriter{}}, "z")
+}
@@ -0,0 +1,66 @@
+//go:build windows
+
+package rich
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const enableVirtualTerminalProcessing = 0x0004
+
+var (
+	kernel32                   = syscall.NewLazyDLL("kernel32.dll")
+	setConsoleMode             = kernel32.NewProc("SetConsoleMode")
+	getConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+)
+
+type consoleScreenBufferInfo struct {
+	size, cursorPosition     [2]int16
+	attributes               uint16
+	left, top, right, bottom int16
+	maximumWindowSize        [2]int16
+}
+
+// Windows consoles only interpret ANSI escapes once virtual terminal
+// processing is switched on for the output handle. It is tried for stdout
+// and for stderr, where Error, Warning and Debug go; a console that refuses
+// it gets plain text.
+func init() {
+	for _, file := range []*os.File{os.Stdout, os.Stderr} {
+		if isTerminal(file) && !enableVirtualTerminal(file) {
+			legacyConsoles[file] = true
+		}
+	}
+
+	if legacyConsoles[os.Stdout] {
+		configure(func(s *settings) { s.color = false })
+	}
+}
+
+func enableVirtualTerminal(file *os.File) bool {
+	handle := syscall.Handle(file.Fd())
+
+	var mode uint32
+	if err := syscall.GetConsoleMode(handle, &mode); err != nil {
+		return false
+	}
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+
+	ok, _, _ := setConsoleMode.Call(uintptr(handle), uintptr(mode|enableVirtualTerminalProcessing))
+
+	return ok != 0
+}
+
+func terminalSize(file *os.File) (int, bool) {
+	var info consoleScreenBufferInfo
+	ok, _, _ := getConsoleScreenBufferInfo.Call(file.Fd(), uintptr(unsafe.Pointer(&info)))
+	if ok == 0 {
+		return 0, false
+	}
+
+	return int(info.right-info.left) + 1, true
+}
@@ -0,0 +1,42 @@
+package rich
+
+import "strconv"
+
+// Cursor helpers write CSI sequences to the output set by SetOutput. Like
+// styling, they are dropped when colors are disabled, so pipes and files
+// never receive them.
+
+// HideCursor writes CSI ?25l.
+func HideCursor() {
+	writeCursor("\033[?25l")
+}
+
+// ShowCursor writes CSI ?25h.
+func ShowCursor() {
+	writeCursor("\033[?25h")
+}
+
+// ClearLine erases the whole current line with CSI 2K and returns the
+// cursor to its first column with a carriage return.
+func ClearLine() {
+	writeCursor("\r\033[2K")
+}
+
+// MoveUp moves the cursor up n lines, keeping its column, with CSI nA.
+func MoveUp(n int) {
+	if n > 0 {
+		writeCursor("\033[" + strconv.Itoa(n) + "A")
+	}
+}
+
+// MoveTo places the cursor at row and col, both counted from 1, with
+// CSI row;colH. Smaller values are taken as 1.
+func MoveTo(row, col int) {
+	writeCursor("\033[" + strconv.Itoa(max(row, 1)) + ";" + strconv.Itoa(max(col, 1)) + "H")
+}
+
+func writeCursor(sequence string) {
+	if config().color {
+		writeOutput(config().output, sequence)
+	}
+}
@@ -0,0 +1,80 @@
+package rich
+
+import "strings"
+
+// Diff line styles: removed and added lines, and the missing newline note.
+const (
+	diffRemovedStyle = "red"
+	diffAddedStyle   = "green"
+	diffNoteStyle    = "dim"
+)
+
+// Diff compares a and b line by line and returns every line of the result
+// prefixed like a unified diff: "-" for lines only in a, "+" for lines only
+// in b and a space for lines in both. When only one side ends with a
+// newline, its last line is shown as changed and followed by a
+// "\ No newline at end of file" note. The lines are printed as they are,
+// not as markup.
+func Diff(a, b string) string {
+	s := config()
+	before, after := diffLines(a), diffLines(b)
+	noteNewline := strings.HasSuffix(a, "\n") != strings.HasSuffix(b, "\n")
+
+	// lengths[i][j] is the length of the longest common subsequence of
+	// before[i:] and after[j:].
+	lengths := make([][]int, len(before)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	var lines []string
+	add := func(style, marker, line string) {
+		text, terminated := strings.CutSuffix(line, "\n")
+		lines = append(lines, s.styled(style, marker+text))
+		if noteNewline && !terminated {
+			lines = append(lines, s.styled(diffNoteStyle, `\ No newline at end of file`))
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(before) || j < len(after) {
+		switch {
+		case i < len(before) && j < len(after) && before[i] == after[j]:
+			add("", " ", before[i])
+			i++
+			j++
+		case j == len(after) || i < len(before) && lengths[i+1][j] >= lengths[i][j+1]:
+			add(diffRemovedStyle, "-", before[i])
+			i++
+		default:
+			add(diffAddedStyle, "+", after[j])
+			j++
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func PrintDiff(a, b string) {
+	writeOutput(config().output, Diff(a, b)+"\n")
+}
+
+// diffLines splits s into lines that keep their newline, so a last line
+// with one never equals a last line without.
+func diffLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
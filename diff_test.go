@@ -0,0 +1,22 @@
+package rich
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name  string
+		color bool
+		want  string
+	}{
+		{"colored", true, " a\n\033[0;31m-b\033[0m\n\033[0;2m\\ No newline at end of file\033[0m\n\033[0;32m+c\033[0m"},
+		{"plain", false, " a\n-b\n\\ No newline at end of file\n+c"},
+	}
+
+	pinColors(t, ProfileTrueColor)
+	for _, test := range tests {
+		SetColorEnabled(test.color)
+		if got := Diff("a\nb", "a\nc\n"); got != test.want {
+			t.Errorf("%s: Diff = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package rich
+
+import (
+	"errors"
+	"strings"
+)
+
+// Error chain styles: the top-level message and each cause below it.
+const (
+	errorMessageStyle = "b red"
+	errorCauseStyle   = "red"
+)
+
+// SprintError renders err followed by the chain of errors it wraps, one
+// indented "↳" line per layer. Errors with an Unwrap() []error method list
+// each of their errors one level deeper, except that errors.Join values
+// are replaced by the errors they join, since their own message only
+// repeats them. Messages spanning
+// several lines are styled and indented line by line. A nil error renders
+// as a dim <nil>.
+func SprintError(err error) string {
+	s := config()
+	if err == nil {
+		return s.formatNil()
+	}
+
+	var lines []string
+	lines = s.appendErrorChain(lines, err, 0)
+
+	return strings.Join(lines, "\n")
+}
+
+func PrintError(err error) {
+	writeOutput(config().output, SprintError(err)+"\n")
+}
+
+func (s *settings) appendErrorChain(lines []string, err error, depth int) []string {
+	var causes []error
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, cause := range joined.Unwrap() {
+			if cause != nil {
+				causes = append(causes, cause)
+			}
+		}
+	} else if cause := errors.Unwrap(err); cause != nil {
+		causes = []error{cause}
+	}
+
+	// The message of an errors.Join value is only its errors' messages, one
+	// per line, so those errors take its place.
+	if len(causes) > 1 && err.Error() == joinedMessage(causes) {
+		for _, cause := range causes {
+			lines = s.appendErrorChain(lines, cause, depth)
+		}
+		return lines
+	}
+
+	style, marker := errorMessageStyle, ""
+	if depth > 0 {
+		style, marker = errorCauseStyle, s.indent(depth)+"↳ "
+	}
+	continuation := strings.Repeat(" ", VisibleWidth(marker))
+	for index, line := range strings.Split(err.Error(), "\n") {
+		if index > 0 {
+			marker = continuation
+		}
+		lines = append(lines, marker+s.styled(style, line))
+	}
+	if s.exceedsDepth(depth + 1) {
+		return lines
+	}
+
+	for _, cause := range causes {
+		lines = s.appendErrorChain(lines, cause, depth+1)
+	}
+
+	return lines
+}
+
+func joinedMessage(errs []error) string {
+	messages := make([]string, len(errs))
+	for index, err := range errs {
+		messages[index] = err.Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
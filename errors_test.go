@@ -0,0 +1,42 @@
+package rich
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSprintError(t *testing.T) {
+	disk, network := errors.New("disk full"), errors.New("net down")
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, "<nil>"},
+		{"single", disk, "disk full"},
+		{"wrapped", fmt.Errorf("save: %w", fmt.Errorf("open: %w", disk)), "save: open: disk full\n  ↳ open: disk full\n    ↳ disk full"},
+		{"joined", errors.Join(disk, network), "disk full\nnet down"},
+		{"wrapped join", fmt.Errorf("save: %w", errors.Join(disk, network)), "save: disk full\nnet down\n  ↳ disk full\n  ↳ net down"},
+		{"joined wraps", errors.Join(fmt.Errorf("save: %w", disk), network), "save: disk full\n  ↳ disk full\nnet down"},
+		{"several %w", fmt.Errorf("sync: %w and %w", disk, network), "sync: disk full and net down\n  ↳ disk full\n  ↳ net down"},
+		{"multi-line cause", fmt.Errorf("save: %w", errors.New("line one\nline two")), "save: line one\nline two\n  ↳ line one\n    line two"},
+	}
+
+	pinColors(t, ProfileTrueColor)
+	SetColorEnabled(false)
+	for _, test := range tests {
+		if got := SprintError(test.err); got != test.want {
+			t.Errorf("%s: SprintError = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSprintErrorStylesEachLine(t *testing.T) {
+	pinColors(t, ProfileTrueColor)
+
+	want := "\033[0;1;31ma\033[0m\n\033[0;1;31mb\033[0m"
+	if got := SprintError(errors.New("a\nb")); got != want {
+		t.Errorf("SprintError = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,100 @@
+package rich
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Highlight styles every case-insensitive occurrence of query in text with
+// style, which can be any tag markup. Text may already be rendered output:
+// escape sequences are skipped when matching, and the styling in effect
+// before a match is restored after it. Occurrences do not overlap; the
+// scan resumes after each match.
+func Highlight(text, query, style string) string {
+	if query == "" {
+		return text
+	}
+
+	return HighlightRegexp(text, regexp.MustCompile("(?i)"+regexp.QuoteMeta(query)), style)
+}
+
+// HighlightRegexp is Highlight for the matches of pattern, which take the
+// leftmost-first non-overlapping order of FindAllStringIndex. Empty matches
+// are ignored.
+func HighlightRegexp(text string, pattern *regexp.Regexp, style string) string {
+	if !config().color || !validTags(style) {
+		return text
+	}
+	stack := applyTags(nil, style)
+	if len(stack) == 0 || styleCodes(stack[0]) == "" {
+		return text
+	}
+	// The style is layered over whatever styling the match already has.
+	open := "\033[" + styleCodes(stack[0]) + "m"
+
+	// Split text into plain runs and escape sequences, matching against
+	// the plain text alone.
+	var plain strings.Builder
+	var tokens []string
+	position := 0
+	for _, loc := range ansiRe.FindAllStringIndex(text, -1) {
+		tokens = append(tokens, text[position:loc[0]], text[loc[0]:loc[1]])
+		plain.WriteString(text[position:loc[0]])
+		position = loc[1]
+	}
+	tokens = append(tokens, text[position:])
+	plain.WriteString(text[position:])
+
+	var matches [][]int
+	for _, match := range pattern.FindAllStringIndex(plain.String(), -1) {
+		if match[0] < match[1] {
+			matches = append(matches, match)
+		}
+	}
+	if len(matches) == 0 {
+		return text
+	}
+
+	var result strings.Builder
+	var state sgrState
+	next, offset, inside := 0, 0, false
+	for index, token := range tokens {
+		if index%2 == 1 {
+			result.WriteString(token)
+			state.update(token)
+			if inside && strings.HasSuffix(token, "m") {
+				result.WriteString(open)
+			}
+			continue
+		}
+
+		for {
+			if inside && offset == matches[next][1] {
+				result.WriteString(resetEscape() + state.opening())
+				inside = false
+				next++
+			}
+			if !inside && next < len(matches) && offset == matches[next][0] {
+				result.WriteString(open)
+				inside = true
+			}
+			if token == "" {
+				break
+			}
+
+			length := len(token)
+			if next < len(matches) {
+				boundary := matches[next][0]
+				if inside {
+					boundary = matches[next][1]
+				}
+				length = min(length, boundary-offset)
+			}
+			result.WriteString(token[:length])
+			token = token[length:]
+			offset += length
+		}
+	}
+
+	return result.String()
+}
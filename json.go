@@ -0,0 +1,128 @@
+package rich
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// JSON token styles. Keys use the theme's "key" entry, so they follow the
+// light or dark theme like map and struct keys do.
+const (
+	jsonKeyStyle    = "key"
+	jsonStringStyle = "green"
+	jsonNumberStyle = "cyan b"
+	jsonTrueStyle   = "green b"
+	jsonFalseStyle  = "red b"
+	jsonNullStyle   = "dim"
+)
+
+// SprintJSON marshals v to indented JSON and highlights it. The text itself
+// stays valid JSON once escapes are stripped; markup inside string values is
+// not interpreted.
+func SprintJSON(v any) (string, error) {
+	data, err := json.MarshalIndent(v, "", strings.Repeat(" ", config().indentWidth))
+	if err != nil {
+		return "", err
+	}
+
+	return config().highlightJSON(string(data)), nil
+}
+
+func PrintJSON(v any) error {
+	out, err := SprintJSON(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = writeOutput(config().output, out+"\n")
+
+	return err
+}
+
+// SetJSONAutoFormat turns on pretty-printing of strings that hold a JSON
+// object or array, such as API payloads: Print indents and highlights them
+// like PrintJSON. Other strings are printed as usual. It is off by default.
+func SetJSONAutoFormat(enabled bool) {
+	configure(func(s *settings) { s.jsonAuto = enabled })
+}
+
+// formatJSONString indents and highlights text when it is a JSON object or
+// array, with continuation lines indented for depth. The first and last
+// bytes are checked before the text is parsed.
+func (s *settings) formatJSONString(text string, depth int) (string, bool) {
+	trimmed := strings.TrimSpace(text)
+	if len(trimmed) < 2 {
+		return "", false
+	}
+	if first, last := trimmed[0], trimmed[len(trimmed)-1]; !(first == '{' && last == '}') && !(first == '[' && last == ']') {
+		return "", false
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, []byte(trimmed), s.indent(depth), strings.Repeat(" ", s.indentWidth)); err != nil {
+		return "", false
+	}
+
+	return s.highlightJSON(indented.String()), true
+}
+
+// highlightJSON styles the tokens of well-formed JSON text.
+func (s *settings) highlightJSON(data string) string {
+	var result strings.Builder
+	for index := 0; index < len(data); {
+		char := data[index]
+		switch {
+		case char == '"':
+			end := jsonStringEnd(data, index)
+			style := jsonStringStyle
+			if strings.HasPrefix(strings.TrimLeft(data[end:], " \t\r\n"), ":") {
+				style = jsonKeyStyle
+			}
+			s.writeJSONToken(&result, data[index:end], style)
+			index = end
+		case char == '-' || char >= '0' && char <= '9':
+			end := index + 1
+			for end < len(data) && strings.IndexByte("+-.eE0123456789", data[end]) >= 0 {
+				end++
+			}
+			s.writeJSONToken(&result, data[index:end], jsonNumberStyle)
+			index = end
+		case strings.HasPrefix(data[index:], "true"):
+			s.writeJSONToken(&result, "true", jsonTrueStyle)
+			index += len("true")
+		case strings.HasPrefix(data[index:], "false"):
+			s.writeJSONToken(&result, "false", jsonFalseStyle)
+			index += len("false")
+		case strings.HasPrefix(data[index:], "null"):
+			s.writeJSONToken(&result, "null", jsonNullStyle)
+			index += len("null")
+		default:
+			result.WriteByte(char)
+			index++
+		}
+	}
+
+	return result.String()
+}
+
+// jsonStringEnd returns the index just past the string literal that opens
+// at start, skipping escaped quotes.
+func jsonStringEnd(data string, start int) int {
+	for index := start + 1; index < len(data); index++ {
+		switch data[index] {
+		case '\\':
+			index++
+		case '"':
+			return index + 1
+		}
+	}
+
+	return len(data)
+}
+
+func (s *settings) writeJSONToken(result *strings.Builder, token, style string) {
+	result.WriteString(s.styleEscape(style))
+	result.WriteString(token)
+	result.WriteString(s.resetEscape())
+}
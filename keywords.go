@@ -0,0 +1,147 @@
+package rich
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Keyword is a word that is highlighted with Style wherever it appears in
+// printed strings. Matching is case-insensitive unless CaseSensitive is set.
+type Keyword struct {
+	Word          string
+	Style         string
+	CaseSensitive bool
+}
+
+type compiledKeyword struct {
+	Keyword
+	code    string
+	pattern *regexp.Regexp
+}
+
+// SetKeywordHighlight turns automatic keyword highlighting in printed
+// strings on or off. Markup tags are still parsed when it is off.
+func SetKeywordHighlight(enabled bool) {
+	configure(func(s *settings) { s.keywordHighlight = enabled })
+}
+
+// registerDefaultKeywords runs from init once the styles are registered.
+func registerDefaultKeywords() {
+	for _, word := range []string{"SUCCESS", "ERROR", "WARNING", "INFO"} {
+		AddKeyword(word, KeywordMap[word])
+	}
+}
+
+// AddKeyword highlights word, matched case-insensitively, with style. The
+// style can be anything a tag accepts, e.g. "yellow", "#ff0000" or "b red".
+func AddKeyword(word, style string) bool {
+	return RegisterKeyword(Keyword{Word: word, Style: style})
+}
+
+// RegisterKeyword adds or replaces a keyword, reporting false when the word
+// is empty or the style is not valid markup.
+func RegisterKeyword(keyword Keyword) bool {
+	if keyword.Word == "" || !validTags(keyword.Style) {
+		return false
+	}
+
+	stack := applyTags(nil, keyword.Style)
+	if len(stack) == 0 || styleCodes(stack[0]) == "" {
+		return false
+	}
+
+	pattern := regexp.QuoteMeta(keyword.Word)
+	if isWordByte(keyword.Word[0]) {
+		pattern = `\b` + pattern
+	}
+	if isWordByte(keyword.Word[len(keyword.Word)-1]) {
+		pattern += `\b`
+	}
+	if !keyword.CaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+
+	compiled := compiledKeyword{
+		Keyword: keyword,
+		code:    styleCodes(stack[0]),
+		pattern: regexp.MustCompile(pattern),
+	}
+	configure(func(s *settings) {
+		s.keywords = maps.Clone(s.keywords)
+		s.keywords[keyword.Word] = compiled
+	})
+
+	return true
+}
+
+func RemoveKeyword(word string) {
+	configure(func(s *settings) {
+		s.keywords = maps.Clone(s.keywords)
+		delete(s.keywords, word)
+	})
+}
+
+// Keywords lists the registered keywords ordered by word.
+func Keywords() []Keyword {
+	keywords := config().keywords
+	list := make([]Keyword, 0, len(keywords))
+	for _, keyword := range keywords {
+		list = append(list, keyword.Keyword)
+	}
+	slices.SortFunc(list, func(a, b Keyword) int {
+		return strings.Compare(a.Word, b.Word)
+	})
+
+	return list
+}
+
+type keywordMatch struct {
+	start, end int
+	code       string
+}
+
+// colorizeKeywords styles every keyword occurrence in text on top of the
+// active stack, restoring the stack right after each match. Overlapping
+// matches resolve to the earliest, then longest, one.
+func (s *settings) colorizeKeywords(text string, stack []tagFrame) string {
+	var matches []keywordMatch
+	for _, keyword := range s.keywords {
+		for _, loc := range keyword.pattern.FindAllStringIndex(text, -1) {
+			matches = append(matches, keywordMatch{start: loc[0], end: loc[1], code: keyword.code})
+		}
+	}
+	if len(matches) == 0 || !s.color {
+		return text
+	}
+
+	slices.SortFunc(matches, func(a, b keywordMatch) int {
+		if a.start != b.start {
+			return a.start - b.start
+		}
+		return b.end - a.end
+	})
+
+	base := stackCodes(stack)
+	var result strings.Builder
+	position := 0
+	for _, match := range matches {
+		if match.start < position {
+			continue
+		}
+		highlighted := stackCodes(append(stack[:len(stack):len(stack)], tagFrame{{code: match.code}}))
+		result.WriteString(text[position:match.start])
+		result.WriteString(sgrTransition(base, highlighted))
+		result.WriteString(text[match.start:match.end])
+		result.WriteString(sgrTransition(highlighted, base))
+		position = match.end
+	}
+	result.WriteString(text[position:])
+
+	return result.String()
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
@@ -0,0 +1,45 @@
+package rich
+
+import "testing"
+
+// logLine mixes markup and plain words with the default keywords, the
+// kind of message keyword highlighting runs on in a logging loop.
+const logLine = "[b]request[/] 42 SUCCESS, cache ERROR ignored, WARNING: retry took 12ms"
+
+func BenchmarkSprint(b *testing.B) {
+	pinColors(b, ProfileTrueColor)
+	b.ReportAllocs()
+
+	for range b.N {
+		Sprint(logLine)
+	}
+}
+
+func BenchmarkSprintNoHighlight(b *testing.B) {
+	pinColors(b, ProfileTrueColor)
+	SetKeywordHighlight(false)
+	b.ReportAllocs()
+
+	for range b.N {
+		Sprint(logLine)
+	}
+}
+
+func BenchmarkColorizeKeywords(b *testing.B) {
+	pinColors(b, ProfileTrueColor)
+	s := config()
+	b.ReportAllocs()
+
+	for range b.N {
+		s.colorizeKeywords(logLine, nil)
+	}
+}
+
+func BenchmarkSprintURL(b *testing.B) {
+	pinColors(b, ProfileTrueColor)
+	b.ReportAllocs()
+
+	for range b.N {
+		Sprint("https://example.com/api?id=42")
+	}
+}
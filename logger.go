@@ -0,0 +1,323 @@
+package rich
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelSuccess
+	LevelWarning
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug:   "DEBUG",
+	LevelInfo:    "INFO",
+	LevelSuccess: "SUCCESS",
+	LevelWarning: "WARNING",
+	LevelError:   "ERROR",
+}
+
+func (level Level) String() string {
+	if name, ok := levelNames[level]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("LEVEL(%d)", int(level))
+}
+
+// Logger writes leveled, prefixed lines to its own Output. A nil Output
+// writes to the package output set by SetOutput, Outputs sends single
+// levels elsewhere, and Prefixes overrides the default level names.
+// With Timestamp set, each line starts with time.Now() formatted using
+// TimestampLayout (time.DateTime by default) in TimestampStyle (gray).
+// Configure a Logger before sharing it between goroutines; logging itself
+// is safe for concurrent use.
+type Logger struct {
+	Output          io.Writer
+	Outputs         map[Level]io.Writer
+	Prefixes        map[Level]string
+	Level           Level
+	Timestamp       bool
+	TimestampLayout string
+	TimestampStyle  string
+
+	mu     sync.RWMutex
+	fields []any
+}
+
+var packageLogger = newPackageLogger()
+
+// newPackageLogger builds the package-level logger, which keeps diagnostics
+// off stdout so they never mix with data a program prints there.
+func newPackageLogger() *atomic.Pointer[Logger] {
+	var pointer atomic.Pointer[Logger]
+	pointer.Store(&Logger{
+		Level: LevelDebug,
+		Outputs: map[Level]io.Writer{
+			LevelWarning: os.Stderr,
+			LevelError:   os.Stderr,
+			LevelDebug:   os.Stderr,
+		},
+	})
+
+	return &pointer
+}
+
+func defaultLogger() *Logger {
+	return packageLogger.Load()
+}
+
+// NewLogger returns a logger writing every level to w, with colors
+// detected for w.
+func NewLogger(w io.Writer) *Logger {
+	return &Logger{Output: w, Level: LevelDebug}
+}
+
+// NewDiscardLogger returns a logger that writes nothing. Like any logger
+// writing to io.Discard, it returns before formatting anything, so it
+// costs next to nothing in tests.
+func NewDiscardLogger() *Logger {
+	return NewLogger(io.Discard)
+}
+
+// SetLogger replaces the logger behind Info, Error and the other
+// package-level log functions, e.g. with NewDiscardLogger in tests; nil
+// restores the default one. SetLevel, SetLevelOutput and the timestamp
+// setters configure whichever logger is installed.
+func SetLogger(l *Logger) {
+	if l == nil {
+		l = newPackageLogger().Load()
+	}
+
+	packageLogger.Store(l)
+}
+
+func GetLogger() *Logger {
+	return defaultLogger()
+}
+
+// SetLevelOutput sends package-level log calls at level to w. By default
+// Error, Warning and Debug write to os.Stderr, and the other levels follow
+// SetOutput; a nil w makes level follow SetOutput too.
+func SetLevelOutput(level Level, w io.Writer) {
+	defaultLogger().update(func(l *Logger) {
+		l.Outputs = maps.Clone(l.Outputs)
+		if w == nil {
+			delete(l.Outputs, level)
+		} else {
+			if l.Outputs == nil {
+				l.Outputs = map[Level]io.Writer{}
+			}
+			l.Outputs[level] = w
+		}
+	})
+}
+
+// SetLevel silences package-level log calls below level. Suppressed calls
+// return before any formatting happens.
+func SetLevel(level Level) {
+	defaultLogger().update(func(l *Logger) { l.Level = level })
+}
+
+func GetLevel() Level {
+	l := defaultLogger()
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.Level
+}
+
+func SetTimestamp(enabled bool) {
+	defaultLogger().update(func(l *Logger) { l.Timestamp = enabled })
+}
+
+func SetTimestampLayout(layout string) {
+	defaultLogger().update(func(l *Logger) { l.TimestampLayout = layout })
+}
+
+func SetTimestampStyle(style string) {
+	defaultLogger().update(func(l *Logger) { l.TimestampStyle = style })
+}
+
+func (l *Logger) update(change func(*Logger)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	change(l)
+}
+
+func (l *Logger) Info(args ...any) {
+	l.logWithPrefix(LevelInfo, nil, args...)
+}
+
+func (l *Logger) Success(args ...any) {
+	l.logWithPrefix(LevelSuccess, nil, args...)
+}
+
+func (l *Logger) Error(args ...any) {
+	l.logWithPrefix(LevelError, nil, args...)
+}
+
+func (l *Logger) Warning(args ...any) {
+	l.logWithPrefix(LevelWarning, nil, args...)
+}
+
+func (l *Logger) Debug(args ...any) {
+	l.logWithPrefix(LevelDebug, nil, args...)
+}
+
+// WithField returns a copy of l that appends key=value to every line it
+// logs, after the message. Fields accumulate across chained calls; later
+// changes to l are not seen by the copy.
+func (l *Logger) WithField(key string, value any) *Logger {
+	return l.with(key, value)
+}
+
+// WithFields is WithField for several fields at once, added in key order.
+func (l *Logger) WithFields(fields map[string]any) *Logger {
+	return l.with(sortedFields(fields)...)
+}
+
+func (l *Logger) with(keyvals ...any) *Logger {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return &Logger{
+		Output:          l.Output,
+		Outputs:         maps.Clone(l.Outputs),
+		Prefixes:        maps.Clone(l.Prefixes),
+		Level:           l.Level,
+		Timestamp:       l.Timestamp,
+		TimestampLayout: l.TimestampLayout,
+		TimestampStyle:  l.TimestampStyle,
+		fields:          append(slices.Clip(l.fields), keyvals...),
+	}
+}
+
+// Log writes message at level followed by key=value pairs taken from
+// keyvals, e.g. Log(LevelInfo, "saved", "id", 7, "took", d). A single
+// map[string]any is accepted instead of pairs. Values are formatted like
+// Print formats them, and a key without a value is shown with a <missing>
+// marker.
+func (l *Logger) Log(level Level, message string, keyvals ...any) {
+	if len(keyvals) == 1 {
+		if fields, ok := keyvals[0].(map[string]any); ok {
+			keyvals = sortedFields(fields)
+		}
+	}
+
+	l.logWithPrefix(level, keyvals, message)
+}
+
+func (l *Logger) logWithPrefix(level Level, keyvals []any, args ...any) {
+	l.mu.RLock()
+	if level < l.Level {
+		l.mu.RUnlock()
+		return
+	}
+	prefix, named := l.Prefixes[level]
+	output, routed := l.Outputs[level]
+	if !routed {
+		output = l.Output
+	}
+	if output == nil {
+		output = config().output
+	}
+	if output == io.Discard {
+		l.mu.RUnlock()
+		return
+	}
+	var timestamp string
+	if l.Timestamp {
+		timestamp = l.timestamp()
+	}
+	if len(l.fields) > 0 {
+		keyvals = append(slices.Clip(l.fields), keyvals...)
+	}
+	l.mu.RUnlock()
+
+	if !named {
+		prefix = level.String()
+	}
+	pad := strings.Repeat(" ", max(8-len(prefix), 0))
+
+	if color, ok := KeywordMap[level.String()]; ok {
+		prefix = fmt.Sprintf("[%s]%s[/]", color, prefix)
+	}
+	prefix += pad
+
+	// The prefix and timestamp are rendered as markup only, without the
+	// keyword highlighting the message gets, so the level name is not styled
+	// a second time.
+	s := settingsFor(output)
+	line := s.parseTags(prefix)
+	if timestamp != "" {
+		line = s.parseTags(timestamp) + " " + line
+	}
+	if len(args) > 0 {
+		line += " " + s.sprintJoined(" ", args...)
+	}
+	if len(keyvals) > 0 {
+		line += " " + s.formatKeyvals(keyvals)
+	}
+
+	writeOutput(output, line+"\n")
+}
+
+// formatKeyvals renders alternating keys and values as key=value pairs,
+// with the keys dimmed.
+func (s *settings) formatKeyvals(keyvals []any) string {
+	pairs := make([]string, 0, (len(keyvals)+1)/2)
+	for index := 0; index < len(keyvals); index += 2 {
+		key := s.styled("dim", fmt.Sprint(keyvals[index])+"=")
+		if index+1 == len(keyvals) {
+			pairs = append(pairs, key+s.parseTags("[dim]<missing>[/]"))
+			break
+		}
+		pairs = append(pairs, key+s.formatValue(reflect.ValueOf(keyvals[index+1]), 0))
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+func (l *Logger) timestamp() string {
+	layout, style := l.TimestampLayout, l.TimestampStyle
+	if layout == "" {
+		layout = time.DateTime
+	}
+	if style == "" {
+		style = "gray"
+	}
+
+	return fmt.Sprintf("[%s]%s[/]", style, time.Now().Format(layout))
+}
+
+// sortedFields flattens fields into key, value pairs ordered by key.
+func sortedFields(fields map[string]any) []any {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	keyvals := make([]any, 0, 2*len(keys))
+	for _, key := range keys {
+		keyvals = append(keyvals, key, fields[key])
+	}
+
+	return keyvals
+}
@@ -0,0 +1,89 @@
+package rich
+
+import "strings"
+
+// Markdown element styles and the glyph that replaces list markers.
+const (
+	markdownHeadingStyle = "b cyan"
+	markdownBoldStyle    = "b"
+	markdownItalicStyle  = "i"
+	markdownCodeStyle    = "x"
+	markdownBullet       = "•"
+)
+
+// Markdown renders a small subset of markdown: # headings, -, * and +
+// bullets, **bold**, *italic* and `code`. Bracket tags keep working in the
+// rest of the text, while code spans are shown verbatim. Unmatched markers
+// are left as they are.
+func Markdown(text string) string {
+	s := config()
+	lines := strings.Split(text, "\n")
+	for index, line := range lines {
+		lines[index] = markdownLine(line)
+	}
+
+	return s.parseTags(strings.Join(lines, "\n"))
+}
+
+func PrintMarkdown(s string) {
+	writeOutput(config().output, Markdown(s)+"\n")
+}
+
+func markdownLine(line string) string {
+	trimmed := strings.TrimLeft(line, " \t")
+	leading := line[:len(line)-len(trimmed)]
+
+	if level := len(trimmed) - len(strings.TrimLeft(trimmed, "#")); level >= 1 && level <= 6 {
+		if heading, ok := strings.CutPrefix(trimmed[level:], " "); ok {
+			return leading + markdownStyled(markdownHeadingStyle, markdownInline(strings.TrimSpace(heading)))
+		}
+	}
+
+	for _, marker := range []string{"- ", "* ", "+ "} {
+		if item, ok := strings.CutPrefix(trimmed, marker); ok {
+			return leading + markdownBullet + " " + markdownInline(item)
+		}
+	}
+
+	return markdownInline(line)
+}
+
+// markdownInline converts code spans, bold and italic runs to markup.
+func markdownInline(text string) string {
+	var result strings.Builder
+	for index := 0; index < len(text); index++ {
+		rest := text[index:]
+		switch {
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				code := strings.NewReplacer("[", "[[", "]", "]]").Replace(rest[1 : end+1])
+				result.WriteString(markdownStyled(markdownCodeStyle, code))
+				index += end + 1
+				continue
+			}
+		case strings.HasPrefix(rest, "**"):
+			if end := strings.Index(rest[2:], "**"); end > 0 {
+				result.WriteString(markdownStyled(markdownBoldStyle, markdownInline(rest[2:end+2])))
+				index += end + 3
+				continue
+			}
+			// An unclosed ** is literal; it must not open an italic run.
+			result.WriteString("**")
+			index++
+			continue
+		case rest[0] == '*' && len(rest) > 1 && rest[1] != ' ':
+			if end := strings.IndexByte(rest[1:], '*'); end > 0 {
+				result.WriteString(markdownStyled(markdownItalicStyle, markdownInline(rest[1:end+1])))
+				index += end + 1
+				continue
+			}
+		}
+		result.WriteByte(text[index])
+	}
+
+	return result.String()
+}
+
+func markdownStyled(style, text string) string {
+	return "[" + style + "]" + text + "[/]"
+}
@@ -0,0 +1,24 @@
+package rich
+
+import "testing"
+
+func TestMarkdownInline(t *testing.T) {
+	tests := []struct {
+		markdown string
+		want     string
+	}{
+		{"**b**", "\033[1mb\033[0m"},
+		{"*i*", "\033[3mi\033[0m"},
+		{"`[x]`", "\033[7m[x]\033[0m"},
+		{"a **b and *c", "a **b and *c"},
+		{"a ** b", "a ** b"},
+		{"*i* **", "\033[3mi\033[23m **"},
+	}
+
+	pinColors(t, ProfileTrueColor)
+	for _, test := range tests {
+		if got := Markdown(test.markdown); got != test.want {
+			t.Errorf("Markdown(%q) = %q, want %q", test.markdown, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package rich
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// NumberFormat selects how integers and floats are printed.
+type NumberFormat int
+
+const (
+	// NumbersRaw prints numbers the way fmt does.
+	NumbersRaw NumberFormat = iota
+	// NumbersGrouped adds thousands separators: 1,234,567.
+	NumbersGrouped
+	// NumbersHuman abbreviates large magnitudes: 1.2K, 3.4M, 5.6B, 7.8T.
+	NumbersHuman
+)
+
+var humanSuffixes = []string{"", "K", "M", "B", "T"}
+
+// SetNumberFormat sets how numbers are printed (NumbersRaw by default).
+func SetNumberFormat(format NumberFormat) {
+	configure(func(s *settings) { s.numberFormat = format })
+}
+
+// SetFloatPrecision fixes the digits printed after the decimal point of
+// floats, and of abbreviated numbers with NumbersHuman (one by default). A
+// negative precision restores the shortest exact representation.
+func SetFloatPrecision(precision int) {
+	configure(func(s *settings) { s.floatPrecision = precision })
+}
+
+// SetRunesAsCharacters prints int32 values, which include runes, that are
+// printable code points as 'x' (120) instead of as plain numbers. It is off
+// by default since an int32 is not always a rune.
+func SetRunesAsCharacters(enabled bool) {
+	configure(func(s *settings) { s.runeChars = enabled })
+}
+
+func numberText(value reflect.Value) string {
+	format, precision := config().numberFormat, config().floatPrecision
+
+	if value.Kind() == reflect.Int32 && config().runeChars {
+		if char := rune(value.Int()); unicode.IsPrint(char) {
+			return fmt.Sprintf("%q (%d)", char, char)
+		}
+	}
+
+	isFloat := value.Kind() == reflect.Float32 || value.Kind() == reflect.Float64
+	if format == NumbersHuman {
+		if magnitude, ok := numberMagnitude(value); ok && math.Abs(magnitude) >= 1000 {
+			return humanize(magnitude, precision)
+		}
+	}
+
+	var text string
+	switch {
+	case isFloat && precision >= 0:
+		text = strconv.FormatFloat(value.Float(), 'f', precision, value.Type().Bits())
+	case isFloat && format != NumbersRaw:
+		text = strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits())
+	default:
+		text = fmt.Sprint(value)
+	}
+
+	if format == NumbersRaw {
+		return text
+	}
+
+	return groupThousands(text)
+}
+
+func numberMagnitude(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		f := value.Float()
+		return f, !math.IsInf(f, 0) && !math.IsNaN(f)
+	}
+
+	return 0, false
+}
+
+// humanize abbreviates magnitude with the largest suffix that keeps it at
+// or above one, dropping trailing zeros after the decimal point.
+func humanize(magnitude float64, precision int) string {
+	if precision < 0 {
+		precision = 1
+	}
+
+	// Compare the rounded value, so 999999 becomes 1M rather than 1,000K.
+	scale := math.Pow(10, float64(precision))
+	index := 0
+	for math.Abs(math.Round(magnitude*scale)/scale) >= 1000 && index < len(humanSuffixes)-1 {
+		magnitude /= 1000
+		index++
+	}
+
+	text := strconv.FormatFloat(magnitude, 'f', precision, 64)
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+
+	return groupThousands(text) + humanSuffixes[index]
+}
+
+// groupThousands inserts commas into the integer part of a decimal number.
+func groupThousands(text string) string {
+	sign, digits := "", text
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		sign, digits = digits[:1], digits[1:]
+	}
+	integer, fraction, hasFraction := strings.Cut(digits, ".")
+	if strings.Trim(integer, "0123456789") != "" {
+		return text
+	}
+
+	var grouped strings.Builder
+	for index, digit := range integer {
+		if index > 0 && (len(integer)-index)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	if hasFraction {
+		grouped.WriteString("." + fraction)
+	}
+
+	return sign + grouped.String()
+}
@@ -0,0 +1,52 @@
+package rich
+
+import "io"
+
+// Options overrides the package configuration for a single call. Unset
+// fields, nil pointers and zero values, keep the current setting.
+type Options struct {
+	Color            *bool
+	KeywordHighlight *bool
+	// MaxDepth limits nesting like SetMaxDepth; a negative value removes
+	// the limit.
+	MaxDepth    int
+	IndentWidth int
+	// Output is where PrintWith writes. Unless Color or SetColorEnabled
+	// decided otherwise, colors follow whether it is a terminal.
+	Output io.Writer
+}
+
+// settings returns a copy of the current settings with opts applied.
+func (opts Options) settings() *settings {
+	s := *config()
+	if opts.Output != nil {
+		s = *settingsFor(opts.Output)
+		s.output = opts.Output
+	}
+	if opts.Color != nil {
+		s.color = *opts.Color
+	}
+	if opts.KeywordHighlight != nil {
+		s.keywordHighlight = *opts.KeywordHighlight
+	}
+	if opts.MaxDepth != 0 {
+		s.maxDepth = opts.MaxDepth
+	}
+	if opts.IndentWidth > 0 {
+		s.indentWidth = opts.IndentWidth
+	}
+
+	return &s
+}
+
+// SprintWith is Sprint with opts applied, leaving the package configuration
+// untouched.
+func SprintWith(opts Options, args ...any) string {
+	return opts.settings().sprintJoined(" ", args...)
+}
+
+// PrintWith is Print with opts applied.
+func PrintWith(opts Options, args ...any) {
+	s := opts.settings()
+	writeOutput(s.output, s.sprintJoined(" ", args...)+"\n")
+}
@@ -0,0 +1,71 @@
+package rich
+
+import "strings"
+
+// Box is the set of glyphs a border is drawn with.
+type Box struct {
+	TopLeft, TopRight, BottomLeft, BottomRight string
+	Horizontal, Vertical                       string
+}
+
+var (
+	RoundedBox = Box{"╭", "╮", "╰", "╯", "─", "│"}
+	SquareBox  = Box{"┌", "┐", "└", "┘", "─", "│"}
+)
+
+// PanelStyle is how Panel draws its frame: the border glyphs, their style
+// as tag markup (none by default) and the maximum total width beyond which
+// content is wrapped, where zero means no cap.
+type PanelStyle struct {
+	Box      Box
+	Border   string
+	MaxWidth int
+}
+
+// SetPanelStyle changes the frame of later panels. The default is an
+// unstyled RoundedBox with no width cap.
+func SetPanelStyle(style PanelStyle) {
+	configure(func(s *settings) { s.panel = style })
+}
+
+// Panel draws a border around content, which may contain markup and span
+// several lines, with title embedded in the top border when non-empty.
+func Panel(content, title string) string {
+	s := config()
+	style, box := s.panel, s.panel.Box
+
+	rendered := s.parseTags(content)
+	if style.MaxWidth > 0 {
+		rendered = Wrap(rendered, max(style.MaxWidth-4, 1))
+	}
+	lines := styledLines(rendered)
+
+	if title != "" {
+		title = " " + s.parseTags(title) + " "
+	}
+
+	width := VisibleWidth(title)
+	for _, line := range lines {
+		width = max(width, VisibleWidth(line))
+	}
+
+	border := func(glyphs string) string {
+		return s.styled(style.Border, glyphs)
+	}
+
+	var result strings.Builder
+	top := box.TopLeft + box.Horizontal
+	result.WriteString(border(top) + title)
+	result.WriteString(border(strings.Repeat(box.Horizontal, width+1-VisibleWidth(title)) + box.TopRight))
+	result.WriteString("\n")
+	for _, line := range lines {
+		result.WriteString(border(box.Vertical) + " " + Pad(line, width, AlignLeft, ' ') + " " + border(box.Vertical) + "\n")
+	}
+	result.WriteString(border(box.BottomLeft + strings.Repeat(box.Horizontal, width+2) + box.BottomRight))
+
+	return result.String()
+}
+
+func PrintPanel(content, title string) {
+	writeOutput(config().output, Panel(content, title)+"\n")
+}
@@ -0,0 +1,12 @@
+package rich
+
+import "testing"
+
+func TestPanelUnstyledBorder(t *testing.T) {
+	pinColors(t, ProfileTrueColor)
+
+	want := "╭─ T ─╮\n│ hi  │\n╰─────╯"
+	if got := Panel("hi", "T"); got != want {
+		t.Errorf("Panel = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,196 @@
+package rich
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// ColorProfile is the range of colors the terminal can show. Colors beyond
+// it, such as #hex and rgb(...) on a 256-color terminal, are downgraded to
+// the nearest one it has.
+type ColorProfile int
+
+const (
+	Profile16 ColorProfile = iota
+	Profile256
+	ProfileTrueColor
+)
+
+// basicColors are the xterm values of the 16 standard colors, in SGR order:
+// 30-37 and then 90-97.
+var basicColors = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// SetColorProfile overrides the detected color profile.
+func SetColorProfile(profile ColorProfile) {
+	configure(func(s *settings) { s.colorProfile = profile })
+}
+
+func GetColorProfile() ColorProfile {
+	return config().colorProfile
+}
+
+// detectColorProfile reads COLORTERM=truecolor or 24bit for truecolor, and
+// TERM for 256 colors (xterm-256color, ...). Other terminals get the 16
+// basic colors, except Windows consoles, which handle truecolor once
+// virtual terminal processing is on. FORCE_COLOR=2 or 3 asks for 256 colors
+// or truecolor outright.
+func detectColorProfile() ColorProfile {
+	switch os.Getenv("FORCE_COLOR") {
+	case "2":
+		return Profile256
+	case "3":
+		return ProfileTrueColor
+	}
+
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return ProfileTrueColor
+	}
+
+	term := os.Getenv("TERM")
+	switch {
+	case strings.HasSuffix(term, "-direct") || os.Getenv("WT_SESSION") != "":
+		return ProfileTrueColor
+	case strings.Contains(term, "256color"):
+		return Profile256
+	case term == "" && runtime.GOOS == "windows":
+		return ProfileTrueColor
+	}
+
+	return Profile16
+}
+
+// rgbCode returns the SGR code for an RGB color at the active profile.
+func rgbCode(r, g, b uint8, background bool) string {
+	switch config().colorProfile {
+	case ProfileTrueColor:
+		prefix := "38;2;"
+		if background {
+			prefix = "48;2;"
+		}
+		return prefix + strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b))
+	case Profile256:
+		return paletteCode(uint8(RGBTo256(r, g, b)), background)
+	}
+
+	return basicCode(uint8(RGBTo16(r, g, b)), background)
+}
+
+// paletteCode returns the SGR code for an xterm palette index, mapping it to
+// the basic colors on 16-color terminals.
+func paletteCode(index uint8, background bool) string {
+	if config().colorProfile == Profile16 {
+		if index >= 16 {
+			r, g, b := paletteToRGB(index)
+			index = uint8(RGBTo16(r, g, b))
+		}
+		return basicCode(index, background)
+	}
+
+	if background {
+		return "48;5;" + strconv.Itoa(int(index))
+	}
+
+	return "38;5;" + strconv.Itoa(int(index))
+}
+
+// basicCode turns a basic color index (0-15) into its 30-37 or 90-97 code,
+// or the 40-47 and 100-107 background ones.
+func basicCode(index uint8, background bool) string {
+	code := 30 + int(index)
+	if index >= 8 {
+		code = 90 + int(index) - 8
+	}
+	if background {
+		code += 10
+	}
+
+	return strconv.Itoa(code)
+}
+
+// RGBTo256 returns the xterm palette index, 16-255, of the entry nearest to
+// the color: the closest of the 6x6x6 color cube (16-231) and the gray ramp
+// (232-255). The basic colors 0-15 are left out since terminals theme them.
+func RGBTo256(r, g, b uint8) int {
+	nearestLevel := func(value uint8) int {
+		best := 0
+		for index, level := range cubeLevels {
+			if absDiff(value, level) < absDiff(value, cubeLevels[best]) {
+				best = index
+			}
+		}
+		return best
+	}
+
+	cr, cg, cb := nearestLevel(r), nearestLevel(g), nearestLevel(b)
+	cube := 16 + 36*cr + 6*cg + cb
+
+	average := (int(r) + int(g) + int(b)) / 3
+	gray := 232 + min(max((average-3)/10, 0), 23)
+
+	target := [3]uint8{r, g, b}
+	if colorDistance(target, paletteColor(uint8(gray))) < colorDistance(target, paletteColor(uint8(cube))) {
+		return gray
+	}
+
+	return cube
+}
+
+// RGBTo16 returns the index, 0-15, of the nearest basic color using the
+// xterm defaults: 0-7 are the normal colors (SGR 30-37) and 8-15 the bright
+// ones (SGR 90-97).
+func RGBTo16(r, g, b uint8) int {
+	target := [3]uint8{r, g, b}
+	best := 0
+	for index, color := range basicColors {
+		if colorDistance(target, color) < colorDistance(target, basicColors[best]) {
+			best = index
+		}
+	}
+
+	return best
+}
+
+func paletteToRGB(index uint8) (uint8, uint8, uint8) {
+	switch {
+	case index < 16:
+		color := basicColors[index]
+		return color[0], color[1], color[2]
+	case index < 232:
+		index -= 16
+		return cubeLevels[index/36], cubeLevels[index/6%6], cubeLevels[index%6]
+	}
+
+	level := 8 + 10*(index-232)
+
+	return level, level, level
+}
+
+func paletteColor(index uint8) [3]uint8 {
+	r, g, b := paletteToRGB(index)
+
+	return [3]uint8{r, g, b}
+}
+
+func colorDistance(a, b [3]uint8) int {
+	dr, dg, db := int(a[0])-int(b[0]), int(a[1])-int(b[1]), int(a[2])-int(b[2])
+
+	return dr*dr + dg*dg + db*db
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+
+	return b - a
+}
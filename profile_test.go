@@ -0,0 +1,85 @@
+package rich
+
+import "testing"
+
+// pinColors turns colors on at profile for the rest of the test, restoring
+// the previous settings when it ends.
+func pinColors(t testing.TB, profile ColorProfile) {
+	t.Helper()
+
+	previous := config()
+	t.Cleanup(func() { current.Store(previous) })
+
+	SetColorEnabled(true)
+	SetColorProfile(profile)
+}
+
+func TestRGBTo256(t *testing.T) {
+	tests := []struct {
+		r, g, b uint8
+		want    int
+	}{
+		{255, 0, 0, 196},
+		{0, 255, 0, 46},
+		{0, 0, 255, 21},
+		{0, 0, 0, 16},
+		{255, 255, 255, 231},
+		{95, 135, 175, 67},
+		{128, 128, 128, 244},
+		{8, 8, 8, 232},
+		{238, 238, 238, 255},
+	}
+
+	for _, test := range tests {
+		if got := RGBTo256(test.r, test.g, test.b); got != test.want {
+			t.Errorf("RGBTo256(%d, %d, %d) = %d, want %d", test.r, test.g, test.b, got, test.want)
+		}
+	}
+}
+
+func TestRGBTo16(t *testing.T) {
+	tests := []struct {
+		r, g, b uint8
+		want    int
+	}{
+		{0, 0, 0, 0},
+		{205, 0, 0, 1},
+		{255, 0, 0, 9},
+		{0, 200, 0, 2},
+		{128, 128, 128, 8},
+		{230, 230, 230, 7},
+		{255, 255, 255, 15},
+		{90, 90, 250, 12},
+	}
+
+	for _, test := range tests {
+		if got := RGBTo16(test.r, test.g, test.b); got != test.want {
+			t.Errorf("RGBTo16(%d, %d, %d) = %d, want %d", test.r, test.g, test.b, got, test.want)
+		}
+	}
+}
+
+func TestColorDowngrade(t *testing.T) {
+	tests := []struct {
+		profile ColorProfile
+		markup  string
+		want    string
+	}{
+		{ProfileTrueColor, "[#ff0000]x", "\033[38;2;255;0;0mx\033[0m"},
+		{ProfileTrueColor, "[bg:rgb(0,0,255)]x", "\033[48;2;0;0;255mx\033[0m"},
+		{Profile256, "[#ff0000]x", "\033[38;5;196mx\033[0m"},
+		{Profile256, "[bg:#808080]x", "\033[48;5;244mx\033[0m"},
+		{Profile256, "[color(42)]x", "\033[38;5;42mx\033[0m"},
+		{Profile16, "[#ff0000]x", "\033[91mx\033[0m"},
+		{Profile16, "[bg:#cd0000]x", "\033[41mx\033[0m"},
+		{Profile16, "[color(196)]x", "\033[91mx\033[0m"},
+		{Profile16, "[color(4)]x", "\033[34mx\033[0m"},
+	}
+
+	for _, test := range tests {
+		pinColors(t, test.profile)
+		if got := Sprint(test.markup); got != test.want {
+			t.Errorf("profile %d: Sprint(%q) = %q, want %q", test.profile, test.markup, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package rich
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ProgressStyle gives the █ cells of a progress bar the Filled markup and
+// the ░ cells the Empty one.
+type ProgressStyle struct {
+	Filled string
+	Empty  string
+}
+
+// SetProgressStyle recolors progress bars, green on dim by default.
+func SetProgressStyle(style ProgressStyle) {
+	configure(func(s *settings) { s.progress = style })
+}
+
+// ProgressBar renders a bar like "[████████░░░░] 66%" that is width cells
+// wide; zero or less fills the terminal width. current is clamped to
+// [0, total].
+func ProgressBar(current, total, width int) string {
+	s := config()
+	ratio := 0.0
+	if total > 0 {
+		ratio = min(max(float64(current)/float64(total), 0), 1)
+	}
+	if width <= 0 {
+		width = max(TerminalWidth()-len("[] 100%"), 1)
+	}
+	filled := int(ratio * float64(width))
+
+	var bar strings.Builder
+	bar.Grow(width*len("█") + 48)
+	bar.WriteByte('[')
+	bar.WriteString(s.styleEscape(s.progress.Filled))
+	for range filled {
+		bar.WriteString("█")
+	}
+	bar.WriteString(s.styleEscape(s.progress.Empty))
+	for range width - filled {
+		bar.WriteString("░")
+	}
+	bar.WriteString(s.resetEscape())
+	bar.WriteString("] ")
+	bar.WriteString(strconv.Itoa(int(ratio * 100)))
+	bar.WriteByte('%')
+
+	return bar.String()
+}
+
+// UpdateProgress redraws the progress bar in place on the current line and
+// moves to a new line once current reaches total.
+func UpdateProgress(current, total, width int) {
+	line := "\r" + ProgressBar(current, total, width)
+	if current >= total {
+		line += "\n"
+	}
+	writeOutput(config().output, line)
+}
@@ -0,0 +1,51 @@
+package rich
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// stdin is shared by every prompt so input buffered past one line is not
+// lost to the next call.
+var (
+	stdinMu sync.Mutex
+	stdin   = bufio.NewReader(os.Stdin)
+)
+
+// Prompt prints message as markup, without a newline, and reads one line of
+// input with the line ending removed. A final line without a newline is
+// still returned; io.EOF is only reported once there is no input left.
+func Prompt(message string) (string, error) {
+	writeOutput(config().output, Sprint(message))
+
+	stdinMu.Lock()
+	defer stdinMu.Unlock()
+
+	line, err := stdin.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// Confirm asks a yes/no question, appending " [y/N] " to message. An empty
+// answer means no; anything other than y, yes, n or no asks again.
+func Confirm(message string) (bool, error) {
+	for {
+		answer, err := Prompt(message + " [[y/N]] ")
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return true, nil
+		case "", "n", "no":
+			return false, nil
+		}
+	}
+}
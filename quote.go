@@ -0,0 +1,37 @@
+package rich
+
+import (
+	"strconv"
+	"strings"
+)
+
+// QuoteStyle selects how strings nested in maps, slices and structs are
+// quoted. Strings printed on their own are never quoted.
+type QuoteStyle int
+
+const (
+	// QuoteDouble quotes and escapes like %q: "a \"b\"\n".
+	QuoteDouble QuoteStyle = iota
+	// QuoteSingle is QuoteDouble with single quotes: 'it\'s'.
+	QuoteSingle
+	// QuoteNone prints nested strings as they are.
+	QuoteNone
+)
+
+// SetQuoteStyle sets how nested strings are quoted (QuoteDouble by default).
+func SetQuoteStyle(style QuoteStyle) {
+	configure(func(s *settings) { s.quoteStyle = style })
+}
+
+func quoteString(text string) string {
+	switch config().quoteStyle {
+	case QuoteDouble:
+		return strconv.Quote(text)
+	case QuoteSingle:
+		quoted := strconv.Quote(text)
+		inner := strings.ReplaceAll(quoted[1:len(quoted)-1], `\"`, `"`)
+		return "'" + strings.ReplaceAll(inner, "'", `\'`) + "'"
+	}
+
+	return text
+}
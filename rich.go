@@ -5,24 +5,33 @@ package rich
 // Inspired by the rich library in Python.
 
 import (
+	"cmp"
 	"fmt"
+	"io"
+	"os"
 	"reflect"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Style struct {
 	Name    string
 	Code    string
 	IsColor bool
+
+	// escape is the full sequence for the style on its own, built at init.
+	escape string
 }
 
 var KeywordMap = map[string]string{
-	"SUCCESS": "green",
-	"ERROR":   "red",
-	"WARNING": "yellow",
-	"INFO":    "cyan",
-	"DEBUG":   "gray",
+	"SUCCESS": "success",
+	"ERROR":   "error",
+	"WARNING": "warning",
+	"INFO":    "info",
+	"DEBUG":   "debug",
 }
 
 var IconMap = map[string]string{
@@ -53,212 +62,1289 @@ var (
 		{Name: "reset", Code: "0", IsColor: false},
 		{Name: "unstyle", Code: "22", IsColor: false},
 		{Name: "b", Code: "1", IsColor: false},
+		{Name: "dim", Code: "2", IsColor: false},
 		{Name: "i", Code: "3", IsColor: false},
 		{Name: "u", Code: "4", IsColor: false},
 		{Name: "s", Code: "9", IsColor: false},
+		{Name: "strikethrough", Code: "9", IsColor: false},
 		{Name: "blink", Code: "5", IsColor: false},
 		{Name: "x", Code: "7", IsColor: false},
+		{Name: "hidden", Code: "8", IsColor: false},
+		{Name: "conceal", Code: "8", IsColor: false},
+		{Name: "overline", Code: "53", IsColor: false},
 		{Name: "white", Code: "97", IsColor: true},
 		{Name: "gray", Code: "37", IsColor: true},
+		{Name: "grey", Code: "37", IsColor: true},
+		{Name: "black", Code: "30", IsColor: true},
 		{Name: "red", Code: "31", IsColor: true},
 		{Name: "green", Code: "32", IsColor: true},
 		{Name: "cyan", Code: "36", IsColor: true},
 		{Name: "blue", Code: "34", IsColor: true},
+		{Name: "magenta", Code: "35", IsColor: true},
 		{Name: "yellow", Code: "33", IsColor: true},
+		{Name: "bright_black", Code: "90", IsColor: true},
+		{Name: "bright_red", Code: "91", IsColor: true},
+		{Name: "bright_green", Code: "92", IsColor: true},
+		{Name: "bright_yellow", Code: "93", IsColor: true},
+		{Name: "bright_blue", Code: "94", IsColor: true},
+		{Name: "bright_magenta", Code: "95", IsColor: true},
+		{Name: "bright_cyan", Code: "96", IsColor: true},
+		{Name: "bright_white", Code: "97", IsColor: true},
 	}
+)
 
-	styleMap = make(map[string]Style)
+var (
+	timeType     = reflect.TypeFor[time.Time]()
+	durationType = reflect.TypeFor[time.Duration]()
 )
 
-var formatterMap map[reflect.Kind]func(reflect.Value) string
+var formatterMap map[reflect.Kind]func(*settings, reflect.Value, int) string
 
 func init() {
+	builtins := make(map[string]Style, len(styles))
 	for _, style := range styles {
-		styleMap[style.Name] = style
-	}
-
-	formatterMap = map[reflect.Kind]func(reflect.Value) string{
-		reflect.String:  formatString,
-		reflect.Bool:    formatBool,
-		reflect.Float32: formatNumber,
-		reflect.Float64: formatNumber,
-		reflect.Int:     formatNumber,
-		reflect.Int8:    formatNumber,
-		reflect.Int16:   formatNumber,
-		reflect.Int32:   formatNumber,
-		reflect.Int64:   formatNumber,
-		reflect.Uint:    formatNumber,
-		reflect.Uint8:   formatNumber,
-		reflect.Uint16:  formatNumber,
-		reflect.Uint32:  formatNumber,
-		reflect.Uint64:  formatNumber,
-		reflect.Map:     formatMap,
-		reflect.Slice:   formatSlice,
-		reflect.Struct:  formatStruct,
+		style.escape = sgr(style.Code)
+		builtins[style.Name] = style
+	}
+	configure(func(s *settings) { s.styles = builtins })
+	registerDefaultKeywords()
+
+	formatterMap = map[reflect.Kind]func(*settings, reflect.Value, int) string{
+		reflect.String:     (*settings).formatString,
+		reflect.Bool:       (*settings).formatBool,
+		reflect.Float32:    (*settings).formatNumber,
+		reflect.Float64:    (*settings).formatNumber,
+		reflect.Int:        (*settings).formatNumber,
+		reflect.Int8:       (*settings).formatNumber,
+		reflect.Int16:      (*settings).formatNumber,
+		reflect.Int32:      (*settings).formatNumber,
+		reflect.Int64:      (*settings).formatNumber,
+		reflect.Uint:       (*settings).formatNumber,
+		reflect.Uint8:      (*settings).formatNumber,
+		reflect.Uint16:     (*settings).formatNumber,
+		reflect.Uint32:     (*settings).formatNumber,
+		reflect.Uint64:     (*settings).formatNumber,
+		reflect.Complex64:  (*settings).formatComplex,
+		reflect.Complex128: (*settings).formatComplex,
+		reflect.Map:        (*settings).formatMap,
+		reflect.Slice:      (*settings).formatSlice,
+		reflect.Array:      (*settings).formatSlice,
+		reflect.Struct:     (*settings).formatStruct,
+		reflect.Pointer:    (*settings).formatPointer,
+	}
+}
+
+// SetMaxDepth sets how many levels of maps, slices and structs are expanded
+// (10 by default); anything deeper is rendered as "...". A depth of zero or
+// less removes the limit.
+func SetMaxDepth(depth int) {
+	configure(func(s *settings) { s.maxDepth = depth })
+}
+
+// SetTimeLayout sets the layout time.Time values are printed with
+// (time.RFC3339 by default).
+func SetTimeLayout(layout string) {
+	configure(func(s *settings) { s.timeLayout = layout })
+}
+
+// SetMaxFields limits how many fields of a struct are printed, summing up
+// the rest in a "... N more fields" line. Zero, the default, prints them
+// all.
+func SetMaxFields(limit int) {
+	configure(func(s *settings) { s.maxFields = max(limit, 0) })
+}
+
+// SetTruncateFields cuts struct field values that fit on one line so that
+// each line of the struct stays within TerminalWidth. It is off by default.
+func SetTruncateFields(enabled bool) {
+	configure(func(s *settings) { s.truncateFields = enabled })
+}
+
+// SetSortMapKeys turns sorting of map keys on (the default) or off, which
+// restores Go's random iteration order.
+func SetSortMapKeys(enabled bool) {
+	configure(func(s *settings) { s.sortMapKeys = enabled })
+}
+
+// SetIndentWidth sets the number of spaces each nesting level is indented by.
+func SetIndentWidth(width int) {
+	configure(func(s *settings) { s.indentWidth = max(width, 0) })
+}
+
+// SetColorEnabled forces colors on or off, overriding the detection done by
+// default and by SetOutput.
+func SetColorEnabled(enabled bool) {
+	configure(func(s *settings) { s.color, s.colorSet = enabled, true })
+}
+
+func ColorEnabled() bool {
+	return config().color
+}
+
+// SetOutput redirects Print, the logging functions and the other package
+// level printers to w; nil restores os.Stdout. Error, Warning and Debug keep
+// writing to os.Stderr unless SetLevelOutput routes them elsewhere. Unless SetColorEnabled was
+// called, colors are detected again for w, so writers that are not
+// terminals get plain text unless FORCE_COLOR is set.
+func SetOutput(w io.Writer) {
+	if w == nil {
+		w = os.Stdout
 	}
+
+	configure(func(s *settings) {
+		s.output = w
+		if !s.colorSet {
+			s.color = detectColor(w)
+		}
+	})
 }
 
+func GetOutput() io.Writer {
+	return config().output
+}
+
+// legacyConsoles holds the terminals that cannot interpret escapes, filled
+// in at init on Windows consoles without virtual terminal processing.
+var legacyConsoles = map[*os.File]bool{}
+
+func detectColor(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if file, ok := w.(*os.File); ok && legacyConsoles[file] {
+		return false
+	}
+
+	if os.Getenv("FORCE_COLOR") != "" {
+		return true
+	}
+
+	file, ok := w.(*os.File)
+
+	return ok && isTerminal(file)
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// tagStyle is one style opened by a tag. Links carry their target instead
+// of an SGR code.
+type tagStyle struct {
+	name string
+	code string
+	link string
+}
+
+// tagFrame is one level of the style stack: the styles opened by a single
+// tag group, kept by name so [/name] can close them individually.
+type tagFrame []tagStyle
+
+// parseTags turns markup into SGR escapes. Every tag group opens one level
+// on the stack; [/] pops the top level and [/name] closes the most recent
+// style with that name, ignoring closes that match nothing. [[ and ]] are
+// escapes for literal brackets, and [link=url] opens an OSC 8 hyperlink.
+// Styling left open at the end of str is reset, so it never bleeds into
+// later output, and styled text is reset before each newline and restyled
+// after it.
 func parseTags(str string) string {
-	var stack []string
-	segments := strings.Split(str, "[")
+	return config().parseTags(str)
+}
+
+func (s *settings) parseTags(str string) string {
+	return s.renderTags(str, false)
+}
+
+// renderTags is parseTags with optional keyword highlighting of the text
+// between tags.
+func (s *settings) renderTags(str string, highlight bool) string {
+	var stack []tagFrame
+	var result strings.Builder
+	var link string
+
+	text, str := cutText(str)
+	if highlight {
+		text = s.colorizeKeywords(text, stack)
+	}
+	result.WriteString(text)
+
+	// active holds the codes last emitted, so unchanged or still unstyled
+	// segments are written without any escape at all.
+	color := s.color
+	var active string
+	for str != "" {
+		tags, rest, _ := cutTag(str)
+		stack = applyTags(stack, tags)
+		text, str = cutText(rest)
+		if highlight {
+			text = s.colorizeKeywords(text, stack)
+		}
+		result.WriteString(s.switchLink(link, currentLink(stack)))
+		link = currentLink(stack)
+		if codes := stackCodes(stack); color && text != "" && codes != active {
+			result.WriteString(sgrTransition(active, codes))
+			active = codes
+		}
+		// Some terminals drop the styling at a newline.
+		if active != "" && strings.Contains(text, "\n") {
+			restyle := "\033[" + active + "m"
+			text = strings.ReplaceAll(text, "\n", sgr("")+"\n"+restyle)
+			if trimmed, ok := strings.CutSuffix(text, restyle); ok {
+				text, active = trimmed, ""
+			}
+		}
+		result.WriteString(text)
+	}
+	if active != "" {
+		result.WriteString(sgr(""))
+	}
+	result.WriteString(s.switchLink(link, ""))
+
+	return result.String()
+}
+
+// cutText returns the literal text up to the next tag, with escaped
+// brackets unescaped, and the remainder of str starting at that tag.
+func cutText(str string) (string, string) {
+	var text strings.Builder
+	for index := 0; index < len(str); index++ {
+		if strings.HasPrefix(str[index:], "[[") || strings.HasPrefix(str[index:], "]]") {
+			text.WriteByte(str[index])
+			index++
+			continue
+		}
+		if str[index] == '[' {
+			if _, _, ok := cutTag(str[index:]); ok {
+				return text.String(), str[index:]
+			}
+		}
+		text.WriteByte(str[index])
+	}
+
+	return text.String(), ""
+}
+
+// cutTag splits "[tags]rest", reporting false when str does not start with
+// a well-formed tag group.
+func cutTag(str string) (string, string, bool) {
+	end := tagEnd(str)
+	if end < 0 {
+		return "", "", false
+	}
+
+	tags := str[1:end]
+	if !validTags(tags) {
+		return "", "", false
+	}
+
+	return tags, str[end+1:], true
+}
+
+// tagEnd returns the index of the "]" closing the tag group that starts str,
+// or -1. Link targets may contain balanced brackets, as IPv6 hosts do.
+func tagEnd(str string) int {
+	inLink, depth := false, 0
+	for index := 1; index < len(str); index++ {
+		switch char := str[index]; {
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			inLink, depth = false, 0
+		case !inLink && (index == 1 || isSpaceByte(str[index-1])) && hasPrefixFold(str[index:], "link="):
+			inLink = true
+		case char == '[':
+			if !inLink {
+				return -1
+			}
+			depth++
+		case char == ']':
+			if depth == 0 {
+				return index
+			}
+			depth--
+		}
+	}
+
+	return -1
+}
+
+// tagFields splits a tag group on whitespace, except inside parentheses, so
+// a tag like [b rgb(255, 0, 0)] keeps its color whole.
+func tagFields(tags string) []string {
+	var fields []string
+	start, depth := -1, 0
+	for index := 0; index < len(tags); index++ {
+		char := tags[index]
+		if isSpaceByte(char) && depth == 0 {
+			if start >= 0 {
+				fields = append(fields, tags[start:index])
+				start = -1
+			}
+			continue
+		}
+
+		if start < 0 {
+			start = index
+		}
+		if hasPrefixFold(tags[start:], "link=") {
+			continue
+		}
+		switch char {
+		case '(':
+			depth++
+		case ')':
+			depth = max(depth-1, 0)
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, tags[start:])
+	}
+
+	return fields
+}
+
+func isSpaceByte(char byte) bool {
+	return char == ' ' || char == '\t' || char == '\n' || char == '\r'
+}
+
+func hasPrefixFold(str, prefix string) bool {
+	return len(str) >= len(prefix) && strings.EqualFold(str[:len(prefix)], prefix)
+}
+
+// normalizeTag lowercases a tag, except for link targets which are
+// case-sensitive.
+func normalizeTag(tag string) string {
+	if hasPrefixFold(tag, "link=") {
+		return "link=" + tag[len("link="):]
+	}
 
-	for index, segment := range segments {
-		if index == 0 {
+	return strings.ToLower(tag)
+}
+
+func applyTags(stack []tagFrame, tags string) []tagFrame {
+	var frame tagFrame
+	for _, tag := range tagFields(tags) {
+		tag = normalizeTag(tag)
+		if tag == "/" {
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		} else if name, ok := strings.CutPrefix(tag, "/"); ok {
+			stack = closeTag(stack, name)
+		} else if link, ok := strings.CutPrefix(tag, "link="); ok {
+			frame = append(frame, tagStyle{name: "link", link: link})
+		} else if code, ok := resolveStyle(tag); ok {
+			frame = append(frame, tagStyle{name: tag, code: code})
+		}
+	}
+
+	if len(frame) > 0 {
+		stack = append(stack, frame)
+	}
+
+	return stack
+}
+
+func closeTag(stack []tagFrame, name string) []tagFrame {
+	for index := len(stack) - 1; index >= 0; index-- {
+		frame := stack[index]
+		for position := len(frame) - 1; position >= 0; position-- {
+			if unalias(frame[position].name) != unalias(name) {
+				continue
+			}
+
+			if len(frame) == 1 {
+				return slices.Delete(stack, index, index+1)
+			}
+
+			stack[index] = slices.Delete(frame, position, position+1)
+			return stack
+		}
+	}
+
+	return stack
+}
+
+// validTags reports whether a tag group is real markup: every token must be
+// a close of a known style or link, a link or a known style, and a bare [/]
+// must stand alone. Anything else, including typos like [blahblah], paths
+// like [/usr/bin] and malformed colors like rgb(300,0,0), is left in the
+// output verbatim.
+func validTags(tags string) bool {
+	fields := tagFields(tags)
+	if len(fields) == 0 {
+		return false
+	}
+
+	for _, tag := range fields {
+		tag = normalizeTag(tag)
+		if tag == "/" {
+			if tags != "/" {
+				return false
+			}
+			continue
+		}
+		if name, ok := strings.CutPrefix(tag, "/"); ok {
+			if _, known := resolveStyle(name); !known && name != "link" {
+				return false
+			}
 			continue
 		}
-		parts := strings.SplitN(segment, "]", 2)
-		if len(parts) != 2 {
+		if link, ok := strings.CutPrefix(tag, "link="); ok {
+			if !validLink(link) {
+				return false
+			}
 			continue
 		}
-		tags, rest := parts[0], parts[1]
-
-		for _, tag := range strings.Fields(tags) {
-			tag = strings.ToLower(strings.Trim(tag, "[]"))
-			if tag == "/" {
-				stack = nil
-			} else if strings.HasPrefix(tag, "/") {
-				if len(stack) > 0 {
-					stack = stack[:len(stack)-1]
-				}
-			} else if style, ok := styleMap[tag]; ok {
-				stack = append(stack, style.Code)
+		if _, ok := resolveStyle(tag); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validLink rejects empty targets and control characters, which could
+// otherwise terminate the OSC sequence early.
+func validLink(link string) bool {
+	if link == "" {
+		return false
+	}
+
+	for _, char := range link {
+		if char < 0x20 || char == 0x7f {
+			return false
+		}
+	}
+
+	return true
+}
+
+func resolveStyle(tag string) (string, bool) {
+	if markup, ok := config().themeEntry(tag); ok {
+		code, ok := resolveThemed(markup)
+		if ok && styleDisabled(tag) {
+			return "", true
+		}
+		return code, ok
+	}
+
+	return resolveBuiltin(tag)
+}
+
+// resolveBuiltin resolves a tag without the theme. Disabled styles resolve
+// to no code, so the tag is still consumed.
+func resolveBuiltin(tag string) (string, bool) {
+	code, ok := resolveCode(tag)
+	if ok && styleDisabled(tag) {
+		return "", true
+	}
+
+	return code, ok
+}
+
+func resolveCode(tag string) (string, bool) {
+	if color, ok := strings.CutPrefix(tag, "bg:"); ok {
+		return resolveColor(color, true)
+	}
+
+	// [sgr:38;5;129] passes raw SGR parameters through; only digits and
+	// semicolons are accepted, so no other control sequence can be injected.
+	if params, ok := strings.CutPrefix(tag, "sgr:"); ok {
+		if !validStyleCode(params) {
+			return "", false
+		}
+		return params, true
+	}
+
+	if style, ok := config().styles[tag]; ok && !style.IsColor {
+		return style.Code, true
+	}
+	if name, ok := tagAliases[tag]; ok {
+		return resolveBuiltin(name)
+	}
+
+	return resolveColor(tag, false)
+}
+
+// resolveColor maps a color name, rgb(...), #hex or color(n) to its SGR code,
+// shifting it into the background range when background is set.
+func resolveColor(color string, background bool) (string, bool) {
+	if style, ok := config().styles[color]; ok && style.IsColor {
+		if !background {
+			return style.Code, true
+		}
+		if code, ok := namedBackgrounds[color]; ok {
+			return code, true
+		}
+		return backgroundCode(style.Code)
+	}
+
+	if r, g, b, ok := parseTruecolor(color); ok {
+		return rgbCode(r, g, b, background), true
+	}
+
+	if index, ok := parsePalette(color); ok {
+		return paletteCode(index, background), true
+	}
+
+	return "", false
+}
+
+// namedBackgrounds holds the background codes that are not foreground+10.
+// White is printed bright (97) in the foreground, but bg:white stays in the
+// standard 40-47 range with the other basic names; bg:bright_white is 107.
+var namedBackgrounds = map[string]string{
+	"white": "47",
+}
+
+// backgroundCode shifts a foreground color code, either a basic color or an
+// extended 38;... one, to its background equivalent.
+func backgroundCode(code string) (string, bool) {
+	if extended, ok := strings.CutPrefix(code, "38;"); ok {
+		return "48;" + extended, true
+	}
+
+	value, err := strconv.Atoi(code)
+	if err != nil {
+		return "", false
+	}
+
+	return strconv.Itoa(value + 10), true
+}
+
+// parsePalette validates an xterm 256-color "color(n)" tag and returns n.
+func parsePalette(tag string) (uint8, bool) {
+	if !strings.HasPrefix(tag, "color(") || !strings.HasSuffix(tag, ")") {
+		return 0, false
+	}
+
+	value, err := strconv.Atoi(strings.TrimSpace(tag[len("color(") : len(tag)-1]))
+	if err != nil || value < 0 || value > 255 {
+		return 0, false
+	}
+
+	return uint8(value), true
+}
+
+// parseTruecolor reads "#rrggbb", the short "#rgb" form or "rgb(r,g,b)".
+func parseTruecolor(tag string) (uint8, uint8, uint8, bool) {
+	if strings.HasPrefix(tag, "#") {
+		return hexToRGB(tag)
+	}
+
+	return parseRGB(tag)
+}
+
+func hexToRGB(hex string) (uint8, uint8, uint8, bool) {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return 0, 0, 0, false
+	}
+
+	var components [3]uint8
+	for index := range components {
+		value, err := strconv.ParseUint(hex[index*2:index*2+2], 16, 8)
+		if err != nil {
+			return 0, 0, 0, false
+		}
+		components[index] = uint8(value)
+	}
+
+	return components[0], components[1], components[2], true
+}
+
+func parseRGB(tag string) (uint8, uint8, uint8, bool) {
+	if !strings.HasPrefix(tag, "rgb(") || !strings.HasSuffix(tag, ")") {
+		return 0, 0, 0, false
+	}
+
+	components := strings.Split(tag[len("rgb("):len(tag)-1], ",")
+	if len(components) != 3 {
+		return 0, 0, 0, false
+	}
+
+	var values [3]uint8
+	for index, component := range components {
+		value, err := strconv.Atoi(strings.TrimSpace(component))
+		if err != nil || value < 0 || value > 255 {
+			return 0, 0, 0, false
+		}
+		values[index] = uint8(value)
+	}
+
+	return values[0], values[1], values[2], true
+}
+
+// applyStyling resets the terminal and re-applies the whole stack, so a
+// popped level stops affecting the text that follows it.
+func (s *settings) applyStyling(str string, stack []tagFrame) string {
+	if !s.color {
+		return str
+	}
+
+	return sgr(stackCodes(stack)) + str
+}
+
+// sgr builds the sequence that resets the terminal and then applies codes.
+func sgr(codes string) string {
+	if codes == "" {
+		return "\033[0m"
+	}
+
+	return "\033[0;" + codes + "m"
+}
+
+// sgrTransition returns the shortest sequence that moves the terminal from
+// the from codes to the to codes. Opening more styles only adds their
+// codes, and closed styles are turned off with their dedicated codes
+// (22 for bold, 39 for the foreground, ...); a full reset is only used for
+// codes that have none.
+func sgrTransition(from, to string) string {
+	switch {
+	case from == to:
+		return ""
+	case from == "":
+		return "\033[" + to + "m"
+	case strings.HasPrefix(to, from+";"):
+		return "\033[" + to[len(from)+1:] + "m"
+	}
+
+	codes, ok := sgrChanges(sgrParams(from), sgrParams(to))
+	if !ok {
+		return sgr(to)
+	}
+
+	return "\033[" + strings.Join(codes, ";") + "m"
+}
+
+// sgrChanges lists the off-codes for the parameters of from that to lacks,
+// followed by every parameter of to that shares an attribute with them or
+// is new, in order, so later colors still take precedence.
+func sgrChanges(from, to []string) ([]string, bool) {
+	removed := slices.Clone(from)
+	added := make([]bool, len(to))
+	for index, param := range to {
+		if position := slices.Index(removed, param); position >= 0 {
+			removed = slices.Delete(removed, position, position+1)
+		} else {
+			added[index] = true
+		}
+	}
+
+	var codes []string
+	touched := map[string]bool{}
+	for _, param := range removed {
+		off, ok := sgrOff(param)
+		if !ok {
+			return nil, false
+		}
+		if !touched[off] {
+			touched[off] = true
+			codes = append(codes, off)
+		}
+	}
+	for index, param := range to {
+		if param == "0" {
+			return nil, false
+		}
+		if off, ok := sgrOff(param); ok && added[index] {
+			touched[off] = true
+		}
+	}
+
+	for index, param := range to {
+		if off, ok := sgrOff(param); added[index] || ok && touched[off] {
+			codes = append(codes, param)
+		}
+	}
+
+	return codes, true
+}
+
+// sgrParams splits an SGR parameter list into single parameters, keeping
+// extended colors such as 38;5;208 together.
+func sgrParams(codes string) []string {
+	if codes == "" {
+		return nil
+	}
+
+	fields := strings.Split(codes, ";")
+	var params []string
+	for index := 0; index < len(fields); index++ {
+		size := 1
+		if (fields[index] == "38" || fields[index] == "48") && index+1 < len(fields) {
+			switch fields[index+1] {
+			case "5":
+				size = 3
+			case "2":
+				size = 5
 			}
 		}
+		end := min(index+size, len(fields))
+		params = append(params, strings.Join(fields[index:end], ";"))
+		index = end - 1
+	}
 
-		segments[index] = applyStyling(rest, stack)
+	return params
+}
+
+// sgrOff returns the code that turns off the attribute set by param.
+func sgrOff(param string) (string, bool) {
+	first, _, _ := strings.Cut(param, ";")
+	code, err := strconv.Atoi(first)
+	if err != nil {
+		return "", false
+	}
+
+	switch {
+	case code == 1 || code == 2:
+		return "22", true
+	case code == 3:
+		return "23", true
+	case code == 4 || code == 21:
+		return "24", true
+	case code == 5 || code == 6:
+		return "25", true
+	case code == 7:
+		return "27", true
+	case code == 8:
+		return "28", true
+	case code == 9:
+		return "29", true
+	case code == 53:
+		return "55", true
+	case code >= 30 && code <= 38 || code >= 90 && code <= 97:
+		return "39", true
+	case code >= 40 && code <= 48 || code >= 100 && code <= 107:
+		return "49", true
 	}
 
-	return strings.Join(segments, "")
+	return "", false
 }
 
-func applyStyling(str string, stack []string) string {
-	return fmt.Sprintf("\033[%sm%s", strings.Join(stack, ";"), str)
+func stackCodes(stack []tagFrame) string {
+	codes := make([]string, 0, len(stack))
+	for _, frame := range stack {
+		if code := styleCodes(frame); code != "" {
+			codes = append(codes, code)
+		}
+	}
+
+	return strings.Join(codes, ";")
+}
+
+// styleEscape resolves markup such as "b red" to its escape sequence, or ""
+// when colors are disabled or the markup is invalid.
+func styleEscape(markup string) string {
+	return config().styleEscape(markup)
+}
+
+func (s *settings) styleEscape(markup string) string {
+	if !s.color {
+		return ""
+	}
+	if style, ok := s.builtinStyle(markup); ok {
+		return style.escape
+	}
+	if !validTags(markup) {
+		return ""
+	}
+
+	stack := applyTags(nil, markup)
+	if stackCodes(stack) == "" {
+		return ""
+	}
+
+	return s.applyStyling("", stack)
 }
 
-func formatValue(value reflect.Value) string {
+// styled wraps text in the escape for markup and a reset. Text is returned
+// as it is when markup yields no escape, so no stray reset is written.
+func (s *settings) styled(markup, text string) string {
+	escape := s.styleEscape(markup)
+	if escape == "" {
+		return text
+	}
+
+	return escape + text + s.resetEscape()
+}
+
+// builtinStyle looks up markup naming a single foreground or attribute
+// style that the theme does not override.
+func (s *settings) builtinStyle(markup string) (Style, bool) {
+	if _, ok := s.themeEntry(markup); ok || s.disabled[markup] {
+		return Style{}, false
+	}
+	style, ok := s.styles[markup]
+
+	return style, ok && style.escape != ""
+}
+
+func resetEscape() string {
+	return config().resetEscape()
+}
+
+func (s *settings) resetEscape() string {
+	if !s.color {
+		return ""
+	}
+
+	return sgr("")
+}
+
+func styleCodes(frame tagFrame) string {
+	codes := make([]string, 0, len(frame))
+	for _, style := range frame {
+		if style.code != "" {
+			codes = append(codes, style.code)
+		}
+	}
+
+	return strings.Join(codes, ";")
+}
+
+func currentLink(stack []tagFrame) string {
+	for index := len(stack) - 1; index >= 0; index-- {
+		for position := len(stack[index]) - 1; position >= 0; position-- {
+			if link := stack[index][position].link; link != "" {
+				return link
+			}
+		}
+	}
+
+	return ""
+}
+
+// switchLink emits the OSC 8 sequences to move from one hyperlink target to
+// another; an empty target means no link. Links are dropped with colors.
+func (s *settings) switchLink(from, to string) string {
+	if !s.color || from == to {
+		return ""
+	}
+
+	var sequence string
+	if from != "" {
+		sequence = "\033]8;;\033\\"
+	}
+	if to != "" {
+		sequence += "\033]8;;" + to + "\033\\"
+	}
+
+	return sequence
+}
+
+// formatValue renders value by its dynamic type: interfaces, such as the
+// elements of a []any or map[string]any, are unwrapped first, and pointers
+// are followed to what they point at.
+func (s *settings) formatValue(value reflect.Value, depth int) string {
+	if !value.IsValid() || (value.Kind() == reflect.Interface && value.IsNil()) {
+		return s.formatNil()
+	}
+	if value.Kind() == reflect.Interface {
+		return s.formatValue(value.Elem(), depth)
+	}
+
+	if value.CanInterface() {
+		switch value.Type() {
+		case timeType:
+			return s.formatTime(value.Interface().(time.Time))
+		case durationType:
+			return s.formatDuration(value.Interface().(time.Duration))
+		}
+	}
+
+	if err, ok := asError(value); ok {
+		return s.formatError(err)
+	}
+
+	if str, ok := stringify(value); ok {
+		return s.parseTags(str)
+	}
+
 	if formatter, ok := formatterMap[value.Kind()]; ok {
-		return formatter(value)
+		return formatter(s, value, depth)
+	}
+
+	return s.formatString(value, depth)
+}
+
+func (s *settings) exceedsDepth(depth int) bool {
+	limit := s.maxDepth
+
+	return limit > 0 && depth >= limit
+}
+
+func (s *settings) indent(depth int) string {
+	return strings.Repeat(" ", depth*s.indentWidth)
+}
+
+func asError(value reflect.Value) (error, bool) {
+	if !value.CanInterface() {
+		return nil, false
 	}
 
-	return formatString(value)
+	switch value.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Pointer, reflect.Interface, reflect.Func, reflect.Chan:
+		if value.IsNil() {
+			return nil, false
+		}
+	}
+
+	err, ok := value.Interface().(error)
+
+	return err, ok
+}
+
+// stringify returns the String() text of composite values that implement
+// fmt.Stringer, the way fmt does. Primitive kinds keep their own styling.
+func stringify(value reflect.Value) (string, bool) {
+	switch value.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return "", false
+		}
+	case reflect.Struct, reflect.Array:
+	default:
+		return "", false
+	}
+
+	if !value.CanInterface() {
+		return "", false
+	}
+
+	if stringer, ok := value.Interface().(fmt.Stringer); ok {
+		return stringer.String(), true
+	}
+
+	return "", false
 }
 
-func formatString(str reflect.Value) string {
-	urlRe := `((http|https):\/\/)?([a-zA-Z0-9.-]+\.[a-zA-Z]{2,})(:[0-9]{1,5})?(\/[^:;\|\s\t]+)?`
-	emailRe := `([a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,})`
-	if matched, _ := regexp.MatchString(urlRe, fmt.Sprintf("%v", str)); matched {
+func (s *settings) formatNil() string {
+	return s.parseTags("[dim]<nil>[/]")
+}
+
+func (s *settings) formatTime(t time.Time) string {
+	return s.parseTags(fmt.Sprintf("[cyan]%s[/]", t.Format(s.timeLayout)))
+}
+
+func (s *settings) formatDuration(d time.Duration) string {
+	return s.parseTags(fmt.Sprintf("[cyan]%s[/]", d.String()))
+}
+
+func (s *settings) formatError(err error) string {
+	return s.parseTags(fmt.Sprintf("[red]%s[/]", err.Error()))
+}
+
+// Compiled once; formatString runs for every printed string.
+var (
+	urlRe   = regexp.MustCompile(`((http|https):\/\/)?([a-zA-Z0-9.-]+\.[a-zA-Z]{2,})(:[0-9]{1,5})?(\/[^:;\|\s\t]+)?`)
+	emailRe = regexp.MustCompile(`([a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,})`)
+)
+
+// formatString renders a string; below the top level it is quoted, see
+// SetQuoteStyle, and with SetJSONAutoFormat JSON text is pretty-printed.
+func (s *settings) formatString(str reflect.Value, depth int) string {
+	text := fmt.Sprint(str)
+	if s.jsonAuto && str.Kind() == reflect.String {
+		if formatted, ok := s.formatJSONString(text, depth); ok {
+			return formatted
+		}
+	}
+	if depth > 0 && str.Kind() == reflect.String {
+		text = quoteString(text)
+	}
+
+	if urlRe.MatchString(text) {
 		for domain, icon := range IconMap {
-			if strings.Contains(fmt.Sprintf("%v", str), domain) {
-				return parseTags(fmt.Sprintf("[cyan]%v %v[/]", icon, str))
+			if strings.Contains(text, domain) {
+				return s.parseTags(fmt.Sprintf("[cyan]%v %v[/]", icon, text))
 			}
 		}
 	}
 
-	if matched, _ := regexp.MatchString(emailRe, fmt.Sprintf("%v", str)); matched {
-		return parseTags(fmt.Sprintf("[cyan]%v %v[/]", IconMap["mail"], str))
+	if emailRe.MatchString(text) {
+		return s.parseTags(fmt.Sprintf("[cyan]%v %v[/]", IconMap["mail"], text))
 	}
 
-	return parseTags(fmt.Sprintf("%v", str))
+	return s.renderTags(text, s.keywordHighlight)
 }
 
-func formatBool(value reflect.Value) string {
+func (s *settings) formatBool(value reflect.Value, _ int) string {
 	if reflect.ValueOf(value.Interface()).Bool() {
-		return parseTags("[green][bold]true[/]")
+		return s.parseTags("[green b]true[/]")
 	}
 
-	return parseTags("[red][bold]false[/]")
+	return s.parseTags("[red b]false[/]")
+}
+
+func (s *settings) formatNumber(value reflect.Value, _ int) string {
+	return s.parseTags(fmt.Sprintf("[cyan b]%s[/]", numberText(value)))
 }
 
-func formatNumber(value reflect.Value) string {
-	return parseTags(fmt.Sprintf("[cyan][bold]%v[/]", value))
+// formatComplex renders complex numbers as (re+imi).
+func (s *settings) formatComplex(value reflect.Value, _ int) string {
+	return s.parseTags(fmt.Sprintf("[cyan b]%v[/]", value))
 }
 
-func formatMap(value reflect.Value) string {
+func (s *settings) formatPointer(value reflect.Value, depth int) string {
+	if value.IsNil() {
+		return s.formatNil()
+	}
+
+	return s.formatValue(value.Elem(), depth)
+}
+
+func (s *settings) formatMap(value reflect.Value, depth int) string {
+	if s.exceedsDepth(depth) {
+		return "{...}"
+	}
+
+	if value.Len() == 0 {
+		return "{}"
+	}
+
+	keys := value.MapKeys()
+	if s.sortMapKeys {
+		sortMapKeys(keys)
+	}
+
 	var result strings.Builder
 	result.WriteString("{\n")
-	for _, key := range value.MapKeys() {
-		leftSideType := reflect.ValueOf(key.Interface()).Kind()
-		rightSideType := reflect.ValueOf(value.MapIndex(key).Interface()).Kind()
-		leftSide := parseTags(fmt.Sprintf("[yellow]%s[/]", formatterMap[leftSideType](key)))
-		rightSide := formatterMap[rightSideType](value.MapIndex(key))
+	for _, key := range keys {
+		label := key
+		if label.Kind() == reflect.Interface && !label.IsNil() {
+			label = label.Elem()
+		}
+		name := fmt.Sprint(label)
+		if label.Kind() != reflect.String {
+			name = s.formatValue(label, depth+1)
+		}
+		leftSide := s.parseTags(fmt.Sprintf("[key]%s[/]", name))
+		rightSide := s.formatValue(value.MapIndex(key), depth+1)
 
-		result.WriteString(fmt.Sprintf("  %s: %s,\n", leftSide, rightSide))
+		result.WriteString(fmt.Sprintf("%s%s: %s,\n", s.indent(depth+1), leftSide, rightSide))
 	}
-	result.WriteString("}")
+	result.WriteString(s.indent(depth) + "}")
 
 	return result.String()
 }
 
-func formatSlice(value reflect.Value) string {
-	var result strings.Builder
-	result.WriteString("[ ")
-	for index := range make([]struct{}, value.Len()) {
-		element := value.Index(index)
-		elementType := reflect.ValueOf(element.Interface()).Kind()
-		result.WriteString(formatterMap[elementType](element))
+// sortMapKeys orders numeric keys by value, strings lexicographically and
+// anything else, including keys of mixed kinds, by their printed form.
+func sortMapKeys(keys []reflect.Value) {
+	slices.SortStableFunc(keys, func(a, b reflect.Value) int {
+		for a.Kind() == reflect.Interface && !a.IsNil() {
+			a = a.Elem()
+		}
+		for b.Kind() == reflect.Interface && !b.IsNil() {
+			b = b.Elem()
+		}
 
-		if index < value.Len()-1 {
-			result.WriteString(", ")
+		if a.Kind() == b.Kind() {
+			switch a.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return cmp.Compare(a.Int(), b.Int())
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				return cmp.Compare(a.Uint(), b.Uint())
+			case reflect.Float32, reflect.Float64:
+				return cmp.Compare(a.Float(), b.Float())
+			case reflect.String:
+				return strings.Compare(a.String(), b.String())
+			}
 		}
+
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	})
+}
+
+// formatSlice renders both slices and fixed-size arrays.
+func (s *settings) formatSlice(value reflect.Value, depth int) string {
+	if s.exceedsDepth(depth) {
+		return "[...]"
 	}
-	result.WriteString(" ]")
+
+	if value.Type().Elem().Kind() == reflect.Uint8 {
+		if formatted, ok := s.formatBytes(value); ok {
+			return formatted
+		}
+	}
+	if value.Len() == 0 {
+		return "[]"
+	}
+
+	elements := make([]string, 0, value.Len())
+	multiline := false
+	for index := range value.Len() {
+		formatted := s.formatValue(value.Index(index), depth+1)
+		multiline = multiline || strings.Contains(formatted, "\n")
+		elements = append(elements, formatted)
+	}
+
+	// Scalars stay on one line; nested containers get a line each.
+	if !multiline {
+		return "[ " + strings.Join(elements, ", ") + " ]"
+	}
+
+	var result strings.Builder
+	result.WriteString("[\n")
+	for _, element := range elements {
+		result.WriteString(fmt.Sprintf("%s%s,\n", s.indent(depth+1), element))
+	}
+	result.WriteString(s.indent(depth) + "]")
 
 	return result.String()
 }
 
-func formatStruct(value reflect.Value) string {
+func (s *settings) formatStruct(value reflect.Value, depth int) string {
+	if s.exceedsDepth(depth) {
+		return "{...}"
+	}
+
 	var result strings.Builder
 	result.WriteString("{\n")
+	shown, hidden := 0, 0
 	for index := range value.NumField() {
-		leftSide := parseTags(fmt.Sprintf("[yellow]%s[/]", value.Type().Field(index).Name))
-		rightSide := formatValue(value.Field(index))
-		result.WriteString(fmt.Sprintf("  %s: %s,\n", leftSide, rightSide))
+		name, ok := fieldName(value.Type().Field(index))
+		if !ok {
+			continue
+		}
+		if s.maxFields > 0 && shown == s.maxFields {
+			hidden++
+			continue
+		}
+		shown++
+
+		leftSide := s.indent(depth+1) + s.parseTags(fmt.Sprintf("[key]%s[/]", name)) + ": "
+		rightSide := s.formatValue(value.Field(index), depth+1)
+		if s.truncateFields && !strings.Contains(rightSide, "\n") {
+			rightSide = Truncate(rightSide, max(TerminalWidth()-VisibleWidth(leftSide)-1, 1))
+		}
+		result.WriteString(leftSide + rightSide + ",\n")
+	}
+	// Like an empty map, a struct with nothing to print is shown as {}. When
+	// fields are hidden, at least one was shown, so the summary line stays.
+	if shown == 0 && hidden == 0 {
+		return "{}"
+	}
+	if hidden > 0 {
+		more := fmt.Sprintf("... %d more fields", hidden)
+		if hidden == 1 {
+			more = "... 1 more field"
+		}
+		result.WriteString(s.indent(depth+1) + s.parseTags("[dim]"+more+"[/]") + "\n")
 	}
-	result.WriteString("}")
+	result.WriteString(s.indent(depth) + "}")
 
 	return result.String()
 }
 
-func Print(args ...any) {
+// fieldName returns the key a struct field is printed under: its json tag
+// name when present, otherwise the Go name. Unexported fields and fields
+// tagged json:"-" are skipped.
+func fieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+
+	return field.Name, true
+}
+
+func Sprint(args ...any) string {
+	return config().sprintJoined(" ", args...)
+}
+
+// Sconcat formats args like Sprint but joins them with no separator.
+func Sconcat(args ...any) string {
+	return config().sprintJoined("", args...)
+}
+
+func (s *settings) sprintJoined(separator string, args ...any) string {
 	formattedStrings := make([]string, 0, len(args))
 
 	for _, arg := range args {
-		formattedStrings = append(formattedStrings, formatValue(reflect.ValueOf(arg)))
+		formattedStrings = append(formattedStrings, s.formatValue(reflect.ValueOf(arg), 0))
 	}
 
-	fmt.Println(strings.Join(formattedStrings, " "))
+	return strings.Join(formattedStrings, separator)
 }
 
-func logWithPrefix(prefix string, args ...any) {
-	var pad string
-	if len(pad) < 10 {
-		pad += strings.Repeat(" ", 8-len(prefix))
-	}
+// Fprint writes args to w like Print, returning the number of bytes written
+// and any write error. Colors are detected for w, as SetOutput does.
+func Fprint(w io.Writer, args ...any) (int, error) {
+	return writeOutput(w, settingsFor(w).sprintJoined(" ", args...)+"\n")
+}
 
-	if color, ok := KeywordMap[prefix]; ok {
-		prefix = fmt.Sprintf("[%s]%s[/]", color, prefix)
-	}
-	prefix += pad
+func Print(args ...any) {
+	Fprint(config().output, args...)
+}
 
-	Print(append([]any{prefix}, args...)...)
+// Concat prints args with no separator between them, so pieces of a line
+// can be assembled exactly: Concat("[b]x[/]", ":", v) prints "x:<v>".
+func Concat(args ...any) {
+	writeOutput(config().output, Sconcat(args...)+"\n")
+}
+
+// Sprintf expands the fmt verbs in format first and then renders the result
+// as markup, so values can be interpolated into styled templates. The
+// expanded text goes through the pipeline once, so a % it contains is never
+// treated as a verb.
+func Sprintf(format string, args ...any) string {
+	return Sprint(fmt.Sprintf(format, args...))
+}
+
+func Fprintf(w io.Writer, format string, args ...any) (int, error) {
+	return writeOutput(w, settingsFor(w).sprintJoined(" ", fmt.Sprintf(format, args...)))
+}
+
+// Printf is like fmt.Printf: unlike Print, no newline is appended.
+func Printf(format string, args ...any) {
+	Fprintf(config().output, format, args...)
 }
 
 func Info(args ...any) {
-	logWithPrefix("INFO", args...)
+	defaultLogger().Info(args...)
 }
 
 func Success(args ...any) {
-	logWithPrefix("SUCCESS", args...)
+	defaultLogger().Success(args...)
 }
 
 func Error(args ...any) {
-	logWithPrefix("ERROR", args...)
+	defaultLogger().Error(args...)
 }
 
 func Warning(args ...any) {
-	logWithPrefix("WARNING", args...)
+	defaultLogger().Warning(args...)
 }
 
 func Debug(args ...any) {
-	logWithPrefix("DEBUG", args...)
+	defaultLogger().Debug(args...)
+}
+
+func Log(level Level, message string, keyvals ...any) {
+	defaultLogger().Log(level, message, keyvals...)
+}
+
+func WithField(key string, value any) *Logger {
+	return defaultLogger().WithField(key, value)
+}
+
+func WithFields(fields map[string]any) *Logger {
+	return defaultLogger().WithFields(fields)
 }
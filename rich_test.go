@@ -0,0 +1,131 @@
+package rich
+
+import "testing"
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name   string
+		markup string
+		want   string
+	}{
+		// Nesting: [/] closes one level and restores the ones beneath it.
+		{"three levels", "[red]a [b]b [u]c[/] d[/] e[/] f", "\033[31ma \033[1mb \033[4mc\033[24m d\033[22m e\033[39m f"},
+		{"named closes out of order", "[red]a [b]b [u]c[/u] d[/red] e[/] f", "\033[31ma \033[1mb \033[4mc\033[24m d\033[39m e\033[22m f"},
+		{"closing the last levels together", "[red]a[b]b[/][/]", "\033[31ma\033[1mb\033[0m"},
+
+		// Closes that name nothing known are text, like unknown tags.
+		{"unknown tag", "a [blahblah] b", "a [blahblah] b"},
+		{"path", "installed to [/usr/local/bin] ok", "installed to [/usr/local/bin] ok"},
+		{"spaced close", "x [/ ] y", "x [/ ] y"},
+		{"close of a color", "[bg:red]x[/bg:red]y", "\033[41mx\033[49my"},
+
+		// Escaped brackets.
+		{"escaped index", "arr[[0]]", "arr[0]"},
+		{"escaped tag", "[[not a tag]]", "[not a tag]"},
+		{"escaped style name", "[[red]]x", "[red]x"},
+		{"escape inside a tag", "[red]a[[1]][/]", "\033[31ma[1]\033[0m"},
+
+		// Multiple styles in one tag keep their order in a single escape.
+		{"styles in order", "[red b u]text[/]", "\033[31;1;4mtext\033[0m"},
+		{"styles reversed", "[u b red]text[/]", "\033[4;1;31mtext\033[0m"},
+		{"closing one style of a tag", "[b red]x[/b]y[/]", "\033[1;31mx\033[22my\033[0m"},
+
+		// Attributes, named, hex, rgb and palette colors in one tag.
+		{"bold hex on hex", "[b #ffffff bg:#202020]label[/]", "\033[1;38;2;255;255;255;48;2;32;32;32mlabel\033[0m"},
+		{"italic rgb on rgb", "[i rgb(1,2,3) bg:rgb(4,5,6)]x[/]", "\033[3;38;2;1;2;3;48;2;4;5;6mx\033[0m"},
+		{"underline name on name", "[u bg:red green]x[/]", "\033[4;41;32mx\033[0m"},
+		{"hex on palette struck", "[#ff0000 bg:color(21) s]x[/]", "\033[38;2;255;0;0;48;5;21;9mx\033[0m"},
+		{"foreground and background white", "[red][bg:white]x[/][/]", "\033[31;47mx\033[0m"},
+
+		// Styles are reset at the end of each line and reopened on the next.
+		{"newline inside a tag", "[red]one\ntwo[/]", "\033[31mone\033[0m\n\033[31mtwo\033[0m"},
+		{"trailing newline", "[red b]one\ntwo\n[/]three", "\033[31;1mone\033[0m\n\033[31;1mtwo\033[0m\nthree"},
+		{"newlines around a tag", "a\n[red]b[/]\nc", "a\n\033[31mb\033[39m\nc"},
+	}
+
+	pinColors(t, ProfileTrueColor)
+	for _, test := range tests {
+		if got := parseTags(test.markup); got != test.want {
+			t.Errorf("%s: parseTags(%q) = %q, want %q", test.name, test.markup, got, test.want)
+		}
+	}
+}
+
+func TestParseTagsPlainText(t *testing.T) {
+	pinColors(t, ProfileTrueColor)
+
+	for _, text := range []string{
+		"",
+		"plain text, no tags",
+		"tabs\tand\nnewlines\r\n",
+		"a]b",
+		"unicode ☃ 中文",
+		"\033[1malready styled\033[0m",
+	} {
+		if got := parseTags(text); got != text {
+			t.Errorf("parseTags(%q) = %q, want it unchanged", text, got)
+		}
+	}
+}
+
+func TestSprintNil(t *testing.T) {
+	var (
+		pointer *int
+		slice   []int
+		dict    map[string]int
+		err     error
+	)
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"nil interface", nil, "\033[2m<nil>\033[0m"},
+		{"nil error", err, "\033[2m<nil>\033[0m"},
+		{"nil pointer", pointer, "\033[2m<nil>\033[0m"},
+		{"nil slice", slice, "[]"},
+		{"nil map", dict, "{}"},
+		{"empty slice", []int{}, "[]"},
+		{"empty map", map[string]int{}, "{}"},
+	}
+
+	pinColors(t, ProfileTrueColor)
+	for _, test := range tests {
+		if got := Sprint(test.value); got != test.want {
+			t.Errorf("%s: Sprint = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSprintStruct(t *testing.T) {
+	type (
+		empty   struct{}
+		private struct{ a, b int }
+		skipped struct {
+			A int `json:"-"`
+		}
+		three struct{ A, B, C int }
+	)
+	tests := []struct {
+		name      string
+		value     any
+		maxFields int
+		want      string
+	}{
+		{"no fields", empty{}, 0, "{}"},
+		{"unexported fields", private{1, 2}, 0, "{}"},
+		{"skipped field", skipped{1}, 0, "{}"},
+		{"hidden fields", three{1, 2, 3}, 1, "{\n  A: 1,\n  ... 2 more fields\n}"},
+	}
+
+	// Plain output keeps the expected strings readable; pinColors still
+	// restores the settings changed here.
+	pinColors(t, ProfileTrueColor)
+	SetColorEnabled(false)
+	for _, test := range tests {
+		SetMaxFields(test.maxFields)
+		if got := Sprint(test.value); got != test.want {
+			t.Errorf("%s: Sprint = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
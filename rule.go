@@ -0,0 +1,53 @@
+package rich
+
+import "strings"
+
+// RuleStyle is how Rule draws its line: the rune it repeats, the markup the
+// line is styled with and the markup around the title.
+type RuleStyle struct {
+	Rune  rune
+	Line  string
+	Title string
+}
+
+// SetRuleStyle sets how rules are drawn; by default a dim ─ line around a
+// bold title.
+func SetRuleStyle(style RuleStyle) {
+	configure(func(s *settings) { s.rule = style })
+}
+
+// Rule returns a horizontal line as wide as the terminal with title, which
+// may contain markup, centered in it. An empty title gives a plain line,
+// and a title too wide for the line is truncated.
+func Rule(title string) string {
+	s := config()
+	width := TerminalWidth()
+	if title == "" {
+		return s.ruleLine(width)
+	}
+
+	rendered := s.parseTags("[" + s.rule.Title + "]" + title + "[/]")
+	if !validTags(s.rule.Title) {
+		rendered = s.parseTags(title)
+	}
+	rendered = Truncate(rendered, max(width-6, 1))
+
+	gap := max(width-VisibleWidth(rendered)-2, 0)
+
+	return s.ruleLine(gap/2) + " " + rendered + " " + s.ruleLine(gap-gap/2)
+}
+
+func PrintRule(title string) {
+	writeOutput(config().output, Rule(title)+"\n")
+}
+
+// ruleLine draws width columns of the rule rune, rounded down for wide
+// runes.
+func (s *settings) ruleLine(width int) string {
+	count := width / max(runeWidth(s.rule.Rune), 1)
+	if count <= 0 {
+		return ""
+	}
+
+	return s.styled(s.rule.Line, strings.Repeat(string(s.rule.Rune), count))
+}
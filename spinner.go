@@ -0,0 +1,174 @@
+package rich
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	SpinnerDots = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	SpinnerLine = []string{"-", "\\", "|", "/"}
+)
+
+// Spinner animates a frame and a message on the current line from a
+// background goroutine. When Output is not a terminal, such as a pipe or a
+// log file, the message is written once on its own line instead, with no
+// frames or cursor control. All methods are safe for concurrent use.
+type Spinner struct {
+	Output   io.Writer
+	Interval time.Duration
+	Style    string
+
+	mu       sync.Mutex
+	frames   []string
+	message  string
+	stop     chan struct{}
+	done     chan struct{}
+	animated bool
+}
+
+func NewSpinner(message string) *Spinner {
+	return &Spinner{
+		Interval: 80 * time.Millisecond,
+		Style:    "cyan",
+		frames:   SpinnerDots,
+		message:  message,
+	}
+}
+
+func (s *Spinner) SetMessage(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.message = message
+}
+
+func (s *Spinner) SetFrames(frames []string) {
+	if len(frames) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.frames = frames
+}
+
+// Start begins the animation and hides the cursor. Starting a running
+// spinner does nothing.
+func (s *Spinner) Start() {
+	s.StartContext(context.Background())
+}
+
+// StartContext is like Start, but the spinner also stops, clearing its line,
+// as soon as ctx is done.
+func (s *Spinner) StartContext(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return
+	}
+
+	stop, done := make(chan struct{}), make(chan struct{})
+	s.stop, s.done = stop, done
+	output := s.output()
+	file, ok := output.(*os.File)
+	s.animated = ok && isTerminal(file)
+	if !s.animated {
+		close(done)
+		writeOutput(output, settingsFor(output).parseTags(s.message)+"\n")
+		return
+	}
+
+	if settingsFor(output).color {
+		writeOutput(output, "\033[?25l")
+	}
+
+	go s.run(stop, done)
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				s.stopRun(stop)
+			case <-done:
+			}
+		}()
+	}
+}
+
+// Stop ends the animation, clears its line and restores the cursor. It
+// returns once the goroutine has exited; stopping an idle spinner does
+// nothing.
+func (s *Spinner) Stop() {
+	s.stopRun(nil)
+}
+
+// stopRun stops the current run, or only the run started with owner when
+// owner is not nil, so a stale context cannot stop a restarted spinner.
+func (s *Spinner) stopRun(owner chan struct{}) {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	if stop == nil || owner != nil && owner != stop {
+		s.mu.Unlock()
+		return
+	}
+	s.stop, s.done = nil, nil
+	animated := s.animated
+	s.mu.Unlock()
+
+	close(stop)
+	<-done
+	if !animated {
+		return
+	}
+
+	if output := s.output(); settingsFor(output).color {
+		writeOutput(output, "\r\033[K\033[?25h")
+	} else {
+		writeOutput(output, "\r")
+	}
+}
+
+func (s *Spinner) run(stop, done chan struct{}) {
+	defer close(done)
+
+	interval := s.Interval
+	if interval <= 0 {
+		interval = 80 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for index := 0; ; index++ {
+		s.draw(index)
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Spinner) draw(index int) {
+	s.mu.Lock()
+	frame := s.frames[index%len(s.frames)]
+	message := s.message
+	s.mu.Unlock()
+
+	output := s.output()
+	cfg := settingsFor(output)
+	line := "\r" + cfg.styled(s.Style, frame) + " " + cfg.parseTags(message)
+	if cfg.color {
+		line += "\033[K"
+	}
+	writeOutput(output, line)
+}
+
+func (s *Spinner) output() io.Writer {
+	if s.Output == nil {
+		return config().output
+	}
+
+	return s.Output
+}
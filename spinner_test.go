@@ -0,0 +1,23 @@
+package rich
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestSpinnerWithoutTerminal(t *testing.T) {
+	var buf bytes.Buffer
+	spinner := NewSpinner("working")
+	spinner.Output = &buf
+	spinner.Interval = time.Millisecond
+
+	spinner.Start()
+	time.Sleep(10 * time.Millisecond)
+	spinner.SetMessage("still working")
+	spinner.Stop()
+
+	if got, want := buf.String(), "working\n"; got != want {
+		t.Errorf("spinner wrote %q, want %q", got, want)
+	}
+}
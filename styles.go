@@ -0,0 +1,121 @@
+package rich
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// RegisterStyle adds a named style, or replaces one including the
+// built-ins, so [name]...[/] works wherever markup is parsed. code is the
+// SGR parameter list, e.g. "1;35" or "38;5;208"; color styles also work with
+// the bg: prefix. It reports false when code is malformed or name is empty
+// or could be confused with other tag syntax.
+func RegisterStyle(name, code string, isColor bool) bool {
+	name = strings.ToLower(name)
+	if !validStyleName(name) || !validStyleCode(code) {
+		return false
+	}
+
+	style := Style{Name: name, Code: code, IsColor: isColor, escape: sgr(code)}
+	configure(func(s *settings) {
+		s.styles = maps.Clone(s.styles)
+		s.styles[name] = style
+	})
+
+	return true
+}
+
+// UnregisterStyle removes a style, built-in or not. Keywords registered with
+// it keep their highlighting.
+func UnregisterStyle(name string) {
+	name = strings.ToLower(name)
+	configure(func(s *settings) {
+		s.styles = maps.Clone(s.styles)
+		delete(s.styles, name)
+	})
+}
+
+// Styles lists the registered styles ordered by name.
+func Styles() []Style {
+	styles := config().styles
+	list := make([]Style, 0, len(styles))
+	for _, style := range styles {
+		list = append(list, style)
+	}
+	slices.SortFunc(list, func(a, b Style) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return list
+}
+
+// DisableStyle makes markup ignore a style, e.g. DisableStyle("blink"):
+// its tags are still removed, but the text they wrap is left unstyled. The
+// name may be a built-in, registered or theme style, an alias, or a color
+// tag such as "bg:red". Keyword highlighting is not affected.
+func DisableStyle(name string) {
+	name = unalias(strings.ToLower(name))
+	configure(func(s *settings) {
+		s.disabled = maps.Clone(s.disabled)
+		if s.disabled == nil {
+			s.disabled = map[string]bool{}
+		}
+		s.disabled[name] = true
+	})
+}
+
+// EnableStyle undoes DisableStyle.
+func EnableStyle(name string) {
+	name = unalias(strings.ToLower(name))
+	configure(func(s *settings) {
+		s.disabled = maps.Clone(s.disabled)
+		delete(s.disabled, name)
+	})
+}
+
+func styleDisabled(tag string) bool {
+	return config().disabled[unalias(tag)]
+}
+
+// tagAliases are long names for the terse built-in tags; a style
+// registered under one of these names takes precedence.
+var tagAliases = map[string]string{
+	"bold":      "b",
+	"italic":    "i",
+	"underline": "u",
+	"strike":    "s",
+	"inverse":   "x",
+	"reverse":   "x",
+}
+
+// Aliases returns the long tag names, such as "bold", mapped to the
+// built-in tag each one stands for.
+func Aliases() map[string]string {
+	return maps.Clone(tagAliases)
+}
+
+// unalias returns the tag an alias stands for, so [b]...[/bold] closes.
+func unalias(name string) string {
+	if target, ok := tagAliases[name]; ok {
+		return target
+	}
+
+	return name
+}
+
+// validStyleName rejects names that contain tag delimiters or would shadow
+// the link tag or the close, bg:, #hex, rgb(...) and color(n) forms.
+func validStyleName(name string) bool {
+	return name != "" && name != "link" && !strings.ContainsAny(name, "[]/=:#() \t\r\n")
+}
+
+func validStyleCode(code string) bool {
+	for _, param := range strings.Split(code, ";") {
+		if param == "" || strings.Trim(param, "0123456789") != "" {
+			return false
+		}
+	}
+
+	return true
+}
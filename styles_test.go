@@ -0,0 +1,34 @@
+package rich
+
+import "testing"
+
+func TestRegisterStyle(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"color_brand", true},
+		{"colorful", true},
+		{"rgba_accent", true},
+		{"Brand", true},
+		{"link", false},
+		{"", false},
+		{"bg:brand", false},
+		{"#brand", false},
+		{"rgb(1,2,3)", false},
+		{"color(5)", false},
+		{"two words", false},
+		{"a/b", false},
+	}
+
+	pinColors(t, ProfileTrueColor)
+	for _, test := range tests {
+		if got := RegisterStyle(test.name, "35", true); got != test.want {
+			t.Errorf("RegisterStyle(%q) = %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	if got, want := parseTags("[color_brand]x[/]"), "\033[35mx\033[0m"; got != want {
+		t.Errorf("parseTags = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,201 @@
+package rich
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignRight
+	AlignCenter
+)
+
+// Table renders rows of cells under styled headers. Cells may contain
+// markup; column widths are measured on the rendered, visible text.
+type Table struct {
+	Headers     []string
+	Rows        [][]string
+	Alignments  []Alignment
+	HeaderStyle string
+	Borderless  bool
+}
+
+func NewTable(headers ...string) *Table {
+	return &Table{Headers: headers, HeaderStyle: "b"}
+}
+
+// TableOf builds a table from a slice or array of structs, or of pointers
+// to structs: one column per exported field, headed by its json tag name
+// when it has one, and one row per element. Cells are formatted the way
+// Print formats values, with nested values kept on one line, and numeric
+// columns are right-aligned. Nil elements give empty rows, and an empty
+// slice renders just the headers. Anything else gives an empty table.
+func TableOf(slice any) *Table {
+	table := NewTable()
+	value := treeElem(reflect.ValueOf(slice))
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return table
+	}
+
+	elemType := value.Type().Elem()
+	if elemType.Kind() == reflect.Pointer {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return table
+	}
+
+	var fields []int
+	for index := range elemType.NumField() {
+		field := elemType.Field(index)
+		name, ok := fieldName(field)
+		if !ok {
+			continue
+		}
+		fields = append(fields, index)
+		table.Headers = append(table.Headers, name)
+		if isNumericKind(field.Type.Kind()) {
+			table.SetAlignment(len(table.Headers)-1, AlignRight)
+		}
+	}
+
+	for index := range value.Len() {
+		element := treeElem(value.Index(index))
+		cells := make([]string, len(fields))
+		if element.Kind() == reflect.Struct {
+			for column, field := range fields {
+				cells[column] = tableCell(element.Field(field))
+			}
+		}
+		table.Rows = append(table.Rows, cells)
+	}
+
+	return table
+}
+
+// tableCell formats a value on a single line, as markup that shows it
+// verbatim.
+func tableCell(value reflect.Value) string {
+	lines := strings.Split(config().formatValue(value, 0), "\n")
+	for index, line := range lines {
+		lines[index] = strings.TrimSpace(line)
+	}
+
+	return escapeMarkup(strings.Join(lines, " "))
+}
+
+func isNumericKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+func (t *Table) AddRow(cells ...string) *Table {
+	t.Rows = append(t.Rows, cells)
+	return t
+}
+
+// SetAlignment sets the alignment of one column; columns default to AlignLeft.
+func (t *Table) SetAlignment(column int, alignment Alignment) *Table {
+	for len(t.Alignments) <= column {
+		t.Alignments = append(t.Alignments, AlignLeft)
+	}
+	t.Alignments[column] = alignment
+
+	return t
+}
+
+func (t *Table) Print() {
+	writeOutput(config().output, t.String()+"\n")
+}
+
+func (t *Table) String() string {
+	columns := len(t.Headers)
+	for _, row := range t.Rows {
+		columns = max(columns, len(row))
+	}
+	if columns == 0 {
+		return ""
+	}
+
+	headers := t.renderRow(t.Headers, columns, t.HeaderStyle)
+	rows := make([][]string, 0, len(t.Rows))
+	for _, row := range t.Rows {
+		rows = append(rows, t.renderRow(row, columns, ""))
+	}
+
+	widths := make([]int, columns)
+	for _, row := range append([][]string{headers}, rows...) {
+		for column, cell := range row {
+			widths[column] = max(widths[column], VisibleWidth(cell))
+		}
+	}
+
+	var lines []string
+	if !t.Borderless {
+		lines = append(lines, t.rule(widths, "┌", "┬", "┐"))
+	}
+	if len(t.Headers) > 0 {
+		lines = append(lines, t.line(headers, widths))
+		if !t.Borderless {
+			lines = append(lines, t.rule(widths, "├", "┼", "┤"))
+		}
+	}
+	for _, row := range rows {
+		lines = append(lines, t.line(row, widths))
+	}
+	if !t.Borderless {
+		lines = append(lines, t.rule(widths, "└", "┴", "┘"))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (t *Table) renderRow(cells []string, columns int, style string) []string {
+	rendered := make([]string, columns)
+	for column := range rendered {
+		if column >= len(cells) {
+			continue
+		}
+		if style != "" {
+			rendered[column] = parseTags(fmt.Sprintf("[%s]%s[/]", style, cells[column]))
+		} else {
+			rendered[column] = parseTags(cells[column])
+		}
+	}
+
+	return rendered
+}
+
+func (t *Table) line(cells []string, widths []int) string {
+	padded := make([]string, len(cells))
+	for column, cell := range cells {
+		alignment := AlignLeft
+		if column < len(t.Alignments) {
+			alignment = t.Alignments[column]
+		}
+		padded[column] = alignText(cell, widths[column], alignment)
+	}
+
+	if t.Borderless {
+		return strings.Join(padded, "  ")
+	}
+
+	return "│ " + strings.Join(padded, " │ ") + " │"
+}
+
+func (t *Table) rule(widths []int, left, middle, right string) string {
+	segments := make([]string, len(widths))
+	for column, width := range widths {
+		segments[column] = strings.Repeat("─", width+2)
+	}
+
+	return left + strings.Join(segments, middle) + right
+}
+
+// alignText pads s with spaces to width visible columns.
+func alignText(s string, width int, alignment Alignment) string {
+	return Pad(s, width, alignment, ' ')
+}
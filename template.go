@@ -0,0 +1,50 @@
+package rich
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var verbRe = regexp.MustCompile(`%[-+# 0]*(\[\d+\])?(\d+|\*)?(\.(\d+|\*)?)?(\[\d+\])?[a-zA-Z%]`)
+
+// Template is a format string whose markup has been rendered ahead of time,
+// so formatting it costs no more than fmt.Sprintf.
+type Template struct {
+	format string
+}
+
+// Compile renders the markup of format once, keeping its fmt verbs for
+// Sprint to fill in. Unlike Sprintf, the formatted arguments are inserted
+// as plain text: markup in them is not interpreted, and verbs cannot be
+// used inside tags. Colors and keyword highlighting are taken from the
+// settings at the time of the call to Compile.
+func Compile(format string) *Template {
+	verbs := verbRe.FindAllString(format, -1)
+	s := config()
+	rendered := s.renderTags(verbRe.ReplaceAllLiteralString(format, "\x00"), s.keywordHighlight)
+
+	var result strings.Builder
+	for index, part := range strings.Split(rendered, "\x00") {
+		result.WriteString(strings.ReplaceAll(part, "%", "%%"))
+		if index < len(verbs) {
+			result.WriteString(verbs[index])
+		}
+	}
+
+	return &Template{format: result.String()}
+}
+
+func (t *Template) Sprint(args ...any) string {
+	return fmt.Sprintf(t.format, args...)
+}
+
+func (t *Template) Fprint(w io.Writer, args ...any) (int, error) {
+	return writeOutput(w, t.Sprint(args...))
+}
+
+// Print writes the formatted template like Printf, without a newline.
+func (t *Template) Print(args ...any) {
+	t.Fprint(config().output, args...)
+}
@@ -0,0 +1,34 @@
+package rich
+
+import "testing"
+
+const templateFormat = "[b]%s[/] [cyan]%d[/] items in [dim]%v[/]"
+
+func BenchmarkTemplateSprint(b *testing.B) {
+	pinColors(b, ProfileTrueColor)
+	template := Compile(templateFormat)
+	b.ReportAllocs()
+
+	for range b.N {
+		template.Sprint("GET /api", 42, "12ms")
+	}
+}
+
+func BenchmarkSprintf(b *testing.B) {
+	pinColors(b, ProfileTrueColor)
+	b.ReportAllocs()
+
+	for range b.N {
+		Sprintf(templateFormat, "GET /api", 42, "12ms")
+	}
+}
+
+func TestTemplateSprint(t *testing.T) {
+	pinColors(t, ProfileTrueColor)
+	SetKeywordHighlight(false)
+
+	want := Sprintf(templateFormat, "GET /api", 42, "12ms")
+	if got := Compile(templateFormat).Sprint("GET /api", 42, "12ms"); got != want {
+		t.Errorf("Template.Sprint = %q, want %q", got, want)
+	}
+}
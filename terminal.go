@@ -0,0 +1,50 @@
+package rich
+
+import (
+	"os"
+	"strconv"
+)
+
+const defaultTerminalWidth = 80
+
+// TerminalWidth returns the column count of the terminal that output goes to
+// (see SetOutput). It falls back to $COLUMNS and then to 80 when the output
+// is not a terminal or the size cannot be read. SetTerminalWidth overrides it for tests and pipes.
+func TerminalWidth() int {
+	if width := config().terminalWidth; width > 0 {
+		return width
+	}
+
+	if file, ok := config().output.(*os.File); ok {
+		if width, ok := terminalSize(file); ok && width > 0 {
+			return width
+		}
+	}
+
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+
+	return defaultTerminalWidth
+}
+
+// SetTerminalWidth makes TerminalWidth return width; zero or less restores
+// detection.
+func SetTerminalWidth(width int) {
+	configure(func(s *settings) { s.terminalWidth = width })
+}
+
+// Bell rings the terminal bell by writing BEL (\a) to the output. Nothing is
+// written when the output is not a terminal.
+func Bell() {
+	if file, ok := config().output.(*os.File); ok && isTerminal(file) {
+		writeOutput(file, "\a")
+	}
+}
+
+// Notify prints message, which may contain markup, in bold and then rings
+// the bell, e.g. when a long task finishes.
+func Notify(message string) {
+	Print(New(message).Bold().String())
+	Bell()
+}
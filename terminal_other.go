@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly || windows)
+
+package rich
+
+import "os"
+
+func terminalSize(*os.File) (int, bool) {
+	return 0, false
+}
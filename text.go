@@ -0,0 +1,299 @@
+package rich
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ansiRe matches the escape sequences that take no space on screen: CSI
+// sequences (SGR colors, cursor movement, erasing, ...), OSC sequences such
+// as hyperlinks and window titles, ended by BEL or ST, and the two-byte
+// escapes, including ESC 7 and ESC 8 to save and restore the cursor.
+var ansiRe = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[78=>@-Z\\^_]`)
+
+// wideRanges lists the East Asian wide and fullwidth blocks (plus emoji)
+// that occupy two terminal columns.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x1F300, 0x1F64F},
+	{0x1F900, 0x1F9FF},
+	{0x20000, 0x2FFFD},
+	{0x30000, 0x3FFFD},
+}
+
+// Strip returns the plain text of s: rich markup is resolved and removed,
+// along with any CSI, OSC or other escape sequences already in the string.
+func Strip(s string) string {
+	return stripANSI(parseTags(s))
+}
+
+// VisibleWidth returns the number of terminal columns s occupies once ANSI
+// escapes are removed. Wide runes count as two columns, combining marks and
+// control characters as zero. A carriage return starts over at the first
+// column, so the widest of the parts it separates is returned. Markup is
+// not interpreted, so pass rendered output (e.g. from Sprint) rather than
+// raw tags.
+func VisibleWidth(s string) int {
+	width, column := 0, 0
+	for _, r := range stripANSI(s) {
+		if r == '\r' {
+			column = 0
+			continue
+		}
+		column += runeWidth(r)
+		width = max(width, column)
+	}
+
+	return width
+}
+
+// Measure returns the widest visible line of s, as VisibleWidth counts it,
+// and its number of lines. A trailing newline ends the last line rather
+// than starting a new one, so "" measures 0, 0 and "a\n" measures 1, 1.
+func Measure(s string) (width, height int) {
+	if s == "" {
+		return 0, 0
+	}
+
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	for _, line := range lines {
+		width = max(width, VisibleWidth(line))
+	}
+
+	return width, len(lines)
+}
+
+func stripANSI(s string) string {
+	return ansiRe.ReplaceAllString(s, "")
+}
+
+// escapeMarkup doubles the brackets in s outside its escape sequences, so
+// rendered text can be passed through parseTags unchanged.
+func escapeMarkup(s string) string {
+	escape := strings.NewReplacer("[", "[[", "]", "]]")
+
+	var result strings.Builder
+	position := 0
+	for _, loc := range ansiRe.FindAllStringIndex(s, -1) {
+		result.WriteString(escape.Replace(s[position:loc[0]]))
+		result.WriteString(s[loc[0]:loc[1]])
+		position = loc[1]
+	}
+	result.WriteString(escape.Replace(s[position:]))
+
+	return result.String()
+}
+
+func runeWidth(r rune) int {
+	if unicode.IsControl(r) || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+
+	for _, wide := range wideRanges {
+		if r >= wide[0] && r <= wide[1] {
+			return 2
+		}
+	}
+
+	return 1
+}
+
+// Truncate cuts s to at most width visible columns, ending it with "…"
+// when anything was cut. Escape sequences and runes are never split, and
+// styling or a hyperlink still open at the cut is closed. Pass rendered
+// output, not raw markup.
+func Truncate(s string, width int) string {
+	return TruncateWith(s, width, "…")
+}
+
+// TruncateWith is Truncate with ellipsis marking the cut instead of "…".
+// When width is narrower than ellipsis, s is cut without one.
+func TruncateWith(s string, width int, ellipsis string) string {
+	if VisibleWidth(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+
+	if VisibleWidth(ellipsis) > width {
+		ellipsis = ""
+	}
+	room := width - VisibleWidth(ellipsis)
+
+	var result strings.Builder
+	var state sgrState
+	var link bool
+	position, full := 0, false
+	cut := func(text string) bool {
+		for _, r := range text {
+			if room < runeWidth(r) {
+				return true
+			}
+			room -= runeWidth(r)
+			result.WriteRune(r)
+		}
+		return false
+	}
+	for _, loc := range ansiRe.FindAllStringIndex(s, -1) {
+		if full = cut(s[position:loc[0]]); full {
+			break
+		}
+		sequence := s[loc[0]:loc[1]]
+		result.WriteString(sequence)
+		state.update(sequence)
+		if target, ok := strings.CutPrefix(sequence, "\033]8;"); ok {
+			link = strings.TrimRight(strings.TrimSuffix(target, "\033\\"), "\a") != ";"
+		}
+		position = loc[1]
+	}
+	if !full {
+		cut(s[position:])
+	}
+
+	result.WriteString(ellipsis + state.closing())
+	if link {
+		result.WriteString("\033]8;;\033\\")
+	}
+
+	return result.String()
+}
+
+// Wrap breaks s on spaces so no line is wider than width visible columns,
+// or than TerminalWidth when width is zero or less; words longer than that
+// are kept whole on their own line. Styling that is open at a break is reset
+// at the end of the line and reopened on the next, so colors neither leak
+// nor drop. Pass rendered output, not raw markup.
+func Wrap(s string, width int) string {
+	if width <= 0 {
+		width = TerminalWidth()
+	}
+
+	var state sgrState
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		lines = append(lines, wrapLine(line, width, &state)...)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func wrapLine(line string, width int, state *sgrState) []string {
+	var lines []string
+	var current strings.Builder
+	currentWidth, hasWord := 0, false
+
+	for _, word := range strings.Split(line, " ") {
+		wordWidth := VisibleWidth(word)
+		if hasWord && currentWidth+1+wordWidth > width {
+			lines = append(lines, current.String()+state.closing())
+			current.Reset()
+			current.WriteString(state.opening())
+			currentWidth, hasWord = 0, false
+		}
+
+		if hasWord {
+			current.WriteByte(' ')
+			currentWidth++
+		}
+		current.WriteString(word)
+		currentWidth += wordWidth
+		hasWord = true
+		state.update(word)
+	}
+
+	return append(lines, current.String())
+}
+
+// styledLines splits s into lines that each carry their own styling: the
+// state open at the end of one line is reopened at the start of the next,
+// and every styled line ends with a reset.
+func styledLines(s string) []string {
+	var state sgrState
+	lines := strings.Split(s, "\n")
+	for index, line := range lines {
+		opening := state.opening()
+		state.update(line)
+		lines[index] = opening + line + state.closing()
+	}
+
+	return lines
+}
+
+// sgrState tracks the SGR sequences in effect since the last reset.
+type sgrState []string
+
+func (s *sgrState) update(text string) {
+	for _, sequence := range ansiRe.FindAllString(text, -1) {
+		if !strings.HasSuffix(sequence, "m") {
+			continue
+		}
+
+		switch params := sequence[2 : len(sequence)-1]; {
+		case params == "" || params == "0":
+			*s = nil
+		case strings.HasPrefix(params, "0;"):
+			*s = sgrState{sequence}
+		default:
+			*s = append(*s, sequence)
+		}
+	}
+}
+
+func (s sgrState) opening() string {
+	return strings.Join(s, "")
+}
+
+func (s sgrState) closing() string {
+	if len(s) == 0 {
+		return ""
+	}
+
+	return "\033[0m"
+}
+
+// PadLeft right-aligns s by filling with spaces on the left until it is
+// width visible columns wide. Strings already that wide are unchanged.
+func PadLeft(s string, width int) string {
+	return Pad(s, width, AlignRight, ' ')
+}
+
+// PadRight left-aligns s by filling with spaces on the right.
+func PadRight(s string, width int) string {
+	return Pad(s, width, AlignLeft, ' ')
+}
+
+// Center fills both sides of s with spaces, favoring the right side when
+// the gap is odd.
+func Center(s string, width int) string {
+	return Pad(s, width, AlignCenter, ' ')
+}
+
+// Pad aligns s within width visible columns by filling the gap with fill.
+// A wide fill rune counts as two columns, so the result may fall one column
+// short of width.
+func Pad(s string, width int, alignment Alignment, fill rune) string {
+	fillWidth := max(runeWidth(fill), 1)
+	gap := max(width-VisibleWidth(s), 0) / fillWidth
+	filler := string(fill)
+
+	switch alignment {
+	case AlignRight:
+		return strings.Repeat(filler, gap) + s
+	case AlignCenter:
+		return strings.Repeat(filler, gap/2) + s + strings.Repeat(filler, gap-gap/2)
+	default:
+		return s + strings.Repeat(filler, gap)
+	}
+}
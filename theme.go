@@ -0,0 +1,131 @@
+package rich
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Theme maps logical style names to markup built from the built-in styles,
+// e.g. "danger": "b red". Themed names are usable anywhere a tag is and
+// take precedence over built-in names.
+type Theme map[string]string
+
+// DefaultTheme suits dark terminal backgrounds. Besides the general purpose
+// names, "key" styles map keys, struct fields and JSON and tree keys, and
+// "success", "warning", "info", "error" and "debug" color the log levels and
+// keywords.
+var DefaultTheme = Theme{
+	"primary": "blue",
+	"success": "green",
+	"warning": "yellow",
+	"danger":  "b red",
+	"info":    "cyan",
+	"muted":   "dim",
+	"key":     "yellow",
+	"error":   "red",
+	"debug":   "gray",
+}
+
+// LightTheme replaces the colors that wash out on light backgrounds with
+// darker shades.
+var LightTheme = Theme{
+	"primary": "blue",
+	"success": "color(28)",
+	"warning": "color(130)",
+	"danger":  "b red",
+	"info":    "color(30)",
+	"muted":   "dim",
+	"key":     "color(94)",
+	"error":   "color(124)",
+	"debug":   "color(242)",
+}
+
+// Background is the brightness of the terminal background.
+type Background int
+
+const (
+	BackgroundDark Background = iota
+	BackgroundLight
+)
+
+var terminalBackground = detectBackground()
+
+// TerminalBackground reports whether the terminal background was detected
+// as dark or light. The theme in effect at startup is chosen from it:
+// LightTheme for light backgrounds, DefaultTheme otherwise.
+func TerminalBackground() Background {
+	return terminalBackground
+}
+
+// detectBackground reads COLORFGBG, set by rxvt, Konsole and others as
+// "foreground;background" palette indices. Backgrounds 7 and 9-15 are the
+// light ones; a missing or malformed value means dark.
+func detectBackground() Background {
+	value := os.Getenv("COLORFGBG")
+	background, err := strconv.Atoi(value[strings.LastIndexByte(value, ';')+1:])
+	if err != nil || background != 7 && (background < 9 || background > 15) {
+		return BackgroundDark
+	}
+
+	return BackgroundLight
+}
+
+func defaultTheme() Theme {
+	if terminalBackground == BackgroundLight {
+		return LightTheme
+	}
+
+	return DefaultTheme
+}
+
+// SetTheme replaces the active theme, including the one picked for the
+// detected background. Names theme leaves out still resolve through that
+// picked theme, so output styled with "key" or "warning" keeps its colors.
+func SetTheme(theme Theme) {
+	normalized := make(Theme, len(theme))
+	for name, markup := range theme {
+		normalized[strings.ToLower(name)] = markup
+	}
+
+	configure(func(s *settings) { s.theme = normalized })
+}
+
+// CurrentTheme returns the active theme, which must not be modified; pass a
+// changed copy to SetTheme instead.
+func CurrentTheme() Theme {
+	return config().theme
+}
+
+// themeEntry looks name up in the active theme and then in the one picked
+// for the terminal background.
+func (s *settings) themeEntry(name string) (string, bool) {
+	if markup, ok := s.theme[name]; ok {
+		return markup, true
+	}
+	markup, ok := defaultTheme()[name]
+
+	return markup, ok
+}
+
+// resolveThemed resolves a theme entry against the registered styles only, so
+// entries can never refer to each other in a cycle.
+func resolveThemed(markup string) (string, bool) {
+	fields := tagFields(strings.ToLower(markup))
+	if len(fields) == 0 {
+		return "", false
+	}
+
+	codes := make([]string, 0, len(fields))
+	for _, field := range fields {
+		code, ok := resolveBuiltin(field)
+		if !ok {
+			return "", false
+		}
+		if code != "" {
+			codes = append(codes, code)
+		}
+	}
+
+	return strings.Join(codes, ";"), true
+}
@@ -0,0 +1,30 @@
+package rich
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestThemeLogLevels(t *testing.T) {
+	tests := []struct {
+		name  string
+		theme Theme
+		want  string
+	}{
+		{"default", DefaultTheme, "\033[31mERROR\033[39m    x\n\033[37mDEBUG\033[39m    y\n"},
+		{"light", LightTheme, "\033[38;5;124mERROR\033[39m    x\n\033[38;5;242mDEBUG\033[39m    y\n"},
+	}
+
+	pinColors(t, ProfileTrueColor)
+	for _, test := range tests {
+		SetTheme(test.theme)
+		var out bytes.Buffer
+		logger := NewLogger(&out)
+		logger.Level = LevelDebug
+		logger.Error("x")
+		logger.Debug("y")
+		if got := out.String(); got != test.want {
+			t.Errorf("%s: log = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package rich
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// TreeStyle covers the parts of a Tree that are not values: Guide for the
+// ├── and │ connectors and Key for map keys, field names and slice indices.
+// Leaf values look the way Print shows them.
+type TreeStyle struct {
+	Guide string
+	Key   string
+}
+
+// SetTreeStyle changes the Tree styles. Guides start out dim and keys use the
+// theme's "key" entry.
+func SetTreeStyle(style TreeStyle) {
+	configure(func(s *settings) { s.tree = style })
+}
+
+type treeNode struct {
+	label string
+	value reflect.Value
+}
+
+// Tree renders nested maps, slices and structs like the tree command, one
+// entry per line with ├──, └── and │ connectors. Map keys are sorted, and
+// containers deeper than SetMaxDepth are collapsed to {...} or [...]. Any
+// other value renders as Print would show it.
+func Tree(v any) string {
+	s := config()
+	value := treeElem(reflect.ValueOf(v))
+	children, ok := s.treeChildren(value)
+	if !ok {
+		return s.formatValue(value, 0)
+	}
+
+	return strings.Join(s.appendTree(nil, children, "", 1), "\n")
+}
+
+func PrintTree(v any) {
+	writeOutput(config().output, Tree(v)+"\n")
+}
+
+func (s *settings) appendTree(lines []string, nodes []treeNode, prefix string, depth int) []string {
+	guide := func(glyphs string) string {
+		return s.styled(s.tree.Guide, glyphs)
+	}
+
+	for index, node := range nodes {
+		connector, next := "├── ", "│   "
+		if index == len(nodes)-1 {
+			connector, next = "└── ", "    "
+		}
+		line := prefix + guide(connector) + s.styled(s.tree.Key, node.label)
+
+		value := treeElem(node.value)
+		children, ok := s.treeChildren(value)
+		switch {
+		case !ok:
+			lines = append(lines, line+": "+s.formatValue(value, 0))
+		case len(children) == 0:
+			lines = append(lines, line+": "+treeMarker(value, ""))
+		case s.exceedsDepth(depth):
+			lines = append(lines, line+": "+treeMarker(value, "..."))
+		default:
+			lines = append(lines, line)
+			lines = s.appendTree(lines, children, prefix+guide(next), depth+1)
+		}
+	}
+
+	return lines
+}
+
+// treeElem unwraps interfaces and non-nil pointers down to the value they
+// hold.
+func treeElem(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Interface || value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return value
+		}
+		value = value.Elem()
+	}
+
+	return value
+}
+
+// treeChildren lists the entries of a container, reporting false for
+// values that are shown as leaves, including times, errors, Stringers and
+// bytes that SetBytesFormat prints as a string or hex.
+func (s *settings) treeChildren(value reflect.Value) ([]treeNode, bool) {
+	switch value.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+	default:
+		return nil, false
+	}
+	if value.Type() == timeType {
+		return nil, false
+	}
+	if _, ok := asError(value); ok {
+		return nil, false
+	}
+	if _, ok := stringify(value); ok {
+		return nil, false
+	}
+
+	var nodes []treeNode
+	switch value.Kind() {
+	case reflect.Map:
+		keys := value.MapKeys()
+		sortMapKeys(keys)
+		for _, key := range keys {
+			nodes = append(nodes, treeNode{label: fmt.Sprint(key), value: value.MapIndex(key)})
+		}
+	case reflect.Slice, reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 && s.bytesFormat != BytesNumbers {
+			return nil, false
+		}
+		for index := range value.Len() {
+			nodes = append(nodes, treeNode{label: strconv.Itoa(index), value: value.Index(index)})
+		}
+	case reflect.Struct:
+		for index := range value.NumField() {
+			if name, ok := fieldName(value.Type().Field(index)); ok {
+				nodes = append(nodes, treeNode{label: name, value: value.Field(index)})
+			}
+		}
+	}
+
+	return nodes, true
+}
+
+// treeMarker stands in for an empty or collapsed container.
+func treeMarker(value reflect.Value, inner string) string {
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		return "[" + inner + "]"
+	}
+
+	return "{" + inner + "}"
+}
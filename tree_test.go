@@ -0,0 +1,27 @@
+package rich
+
+import "testing"
+
+func TestTreeBytes(t *testing.T) {
+	tests := []struct {
+		name   string
+		format BytesFormat
+		value  any
+		want   string
+	}{
+		{"text slice", BytesAuto, map[string]any{"raw": []byte("hi")}, "└── raw: \"hi\""},
+		{"binary array", BytesAuto, map[string]any{"sum": [2]byte{1, 2}}, "└── sum: 0x0102"},
+		{"hex", BytesHex, map[string]any{"raw": []byte("hi")}, "└── raw: 0x6869"},
+		{"numbers", BytesNumbers, map[string]any{"raw": []byte("hi")}, "└── raw\n    ├── 0: 104\n    └── 1: 105"},
+		{"other slices", BytesAuto, map[string]any{"n": []int{1}}, "└── n\n    └── 0: 1"},
+	}
+
+	pinColors(t, ProfileTrueColor)
+	SetColorEnabled(false)
+	for _, test := range tests {
+		SetBytesFormat(test.format)
+		if got := Tree(test.value); got != test.want {
+			t.Errorf("%s: Tree = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
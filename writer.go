@@ -0,0 +1,72 @@
+package rich
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// Writer renders markup written to it, line by line, before passing it on
+// to the underlying writer, so anything that takes an io.Writer, such as
+// log.SetOutput, can print styled text. A line is held back until its
+// newline arrives, which keeps tags split across writes intact; each line
+// is rendered on its own, like a separate Fprint to the underlying writer,
+// so colors are detected for that writer. Keyword highlighting follows
+// SetKeywordHighlight.
+type Writer struct {
+	mu      sync.Mutex
+	w       io.Writer
+	pending []byte
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write renders every line completed by p and writes them in a single call.
+// The incomplete end of p is kept for the next Write or Flush. Since all of
+// p is consumed, the returned count is len(p) even when the underlying
+// write fails.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending = append(w.pending, p...)
+	end := bytes.LastIndexByte(w.pending, '\n')
+	if end < 0 {
+		return len(p), nil
+	}
+
+	var rendered []byte
+	for _, line := range bytes.SplitAfter(w.pending[:end+1], []byte("\n")) {
+		rendered = append(rendered, w.renderLine(line)...)
+	}
+	w.pending = append(w.pending[:0], w.pending[end+1:]...)
+
+	_, err := writeOutput(w.w, string(rendered))
+
+	return len(p), err
+}
+
+// Flush renders and writes the incomplete last line, if any, without adding
+// a newline. A tag cut off at that point is written as literal text.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.pending) == 0 {
+		return nil
+	}
+	line := w.renderLine(w.pending)
+	w.pending = w.pending[:0]
+
+	_, err := writeOutput(w.w, line)
+
+	return err
+}
+
+func (w *Writer) renderLine(line []byte) string {
+	s := settingsFor(w.w)
+
+	return s.renderTags(string(line), s.keywordHighlight)
+}
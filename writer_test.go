@@ -0,0 +1,25 @@
+package rich
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriterDetectsColorsForItsWriter(t *testing.T) {
+	previous := config()
+	t.Cleanup(func() { current.Store(previous) })
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	// Colors are on for stdout, but a buffer is not a terminal.
+	configure(func(s *settings) { s.color, s.colorSet = true, false })
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.Write([]byte("[red]split"))
+	w.Write([]byte(" line[/]\n[b]tail"))
+	w.Flush()
+
+	if got, want := buf.String(), "split line\ntail"; got != want {
+		t.Errorf("Writer wrote %q, want %q", got, want)
+	}
+}